@@ -0,0 +1,97 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+/*
+CurveAdapter wraps an EllipticCurve so that it satisfies crypto/elliptic.Curve, which is what
+elliptic.GenerateKey and anything written against the golang ECC lib expect. The arithmetic is done by the
+wrapped curve, so A doesn't have to be -3.
+
+crypto/elliptic represents the point at infinity as (0,0), so the adapter maps (0,0) to Infinity()
+on the way in and the point at infinity to (0,0) on the way out. On a curve with B=0, where (0,0)
+is an ordinary point of order 2, that point can't be passed through the adapter.
+
+Params() returns elliptic.CurveParams, which has no A. Don't call the methods of the returned
+CurveParams, they assume A=-3 and are only right for curves where that's true.
+*/
+type CurveAdapter struct {
+	Curve  *EllipticCurve
+	params *elliptic.CurveParams
+}
+
+var _ elliptic.Curve = (*CurveAdapter)(nil)
+
+// NewCurveAdapter returns an adapter for curve, which needs G and N for Params and ScalarBaseMult.
+func NewCurveAdapter(curve *EllipticCurve) *CurveAdapter {
+	params := &elliptic.CurveParams{
+		P:       curve.P,
+		N:       curve.N,
+		B:       curve.B,
+		BitSize: curve.BitSize,
+		Name:    curve.Name,
+	}
+	if curve.G != nil {
+		params.Gx, params.Gy = curve.G.X, curve.G.Y
+	}
+	return &CurveAdapter{Curve: curve, params: params}
+}
+
+// Params returns the curve parameters, without A, see CurveAdapter.
+func (a *CurveAdapter) Params() *elliptic.CurveParams {
+	return a.params
+}
+
+/*
+IsOnCurve returns true if (x,y) is on the curve, (0,0) is only on the curve if B=0. Like golang,
+the coordinates must be reduced, i.e. in [0, P), (x+P,y) is not on the curve.
+*/
+func (a *CurveAdapter) IsOnCurve(x, y *big.Int) bool {
+	P := a.Curve.P
+	if x.Sign() < 0 || x.Cmp(P) >= 0 || y.Sign() < 0 || y.Cmp(P) >= 0 {
+		return false
+	}
+	return a.Curve.IsOnCurve(&Point{X: x, Y: y})
+}
+
+// Add returns (x1,y1)+(x2,y2).
+func (a *CurveAdapter) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	return fromPoint(a.Curve.Add(toPoint(x1, y1), toPoint(x2, y2)))
+}
+
+// Double returns 2(x1,y1).
+func (a *CurveAdapter) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	p := toPoint(x1, y1)
+	return fromPoint(a.Curve.Add(p, p))
+}
+
+// ScalarMult returns k(x1,y1), with the Montgomery ladder since k is usually a private key.
+func (a *CurveAdapter) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	return fromPoint(a.Curve.ScalarMultLadder(k, toPoint(x1, y1)))
+}
+
+/*
+ScalarBaseMult returns kG, with the Montgomery ladder like ScalarMult: elliptic.GenerateKey and
+signing code pass private keys and nonces here, so not the faster table of EllipticCurve.ScalarBaseMult.
+*/
+func (a *CurveAdapter) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	return fromPoint(a.Curve.ScalarMultLadder(k, a.Curve.G))
+}
+
+// toPoint converts golang's (x,y) to a Point, (0,0) is the point at infinity.
+func toPoint(x, y *big.Int) *Point {
+	if len(x.Bits()) == 0 && len(y.Bits()) == 0 {
+		return Infinity()
+	}
+	return &Point{X: x, Y: y}
+}
+
+// fromPoint converts p to golang's (x,y), the point at infinity is (0,0).
+func fromPoint(p *Point) (x, y *big.Int) {
+	if p.Infinity {
+		return new(big.Int), new(big.Int)
+	}
+	return p.X, p.Y
+}
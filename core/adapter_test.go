@@ -0,0 +1,103 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCurveAdapterP256 checks the adapter against golang's P-256.
+func TestCurveAdapterP256(t *testing.T) {
+	p256 := elliptic.P256()
+	adapter := NewCurveAdapter(P256())
+	assert.Equal(t, p256.Params().P, adapter.Params().P)
+	assert.Equal(t, p256.Params().N, adapter.Params().N)
+	assert.Equal(t, p256.Params().Gx, adapter.Params().Gx)
+	for i := 0; i < 10; i++ {
+		k1 := make([]byte, 32)
+		k2 := make([]byte, 32)
+		_, _ = rand.Read(k1)
+		_, _ = rand.Read(k2)
+		x1, y1 := p256.ScalarBaseMult(k1)
+		x2, y2 := p256.ScalarBaseMult(k2)
+		assert.True(t, adapter.IsOnCurve(x1, y1))
+		// unreduced coordinates are rejected, like golang does
+		xP := new(big.Int).Add(x1, p256.Params().P)
+		assert.False(t, p256.IsOnCurve(xP, y1))
+		assert.False(t, adapter.IsOnCurve(xP, y1))
+		assert.False(t, adapter.IsOnCurve(x1, new(big.Int).Add(y1, p256.Params().P)))
+		assert.False(t, adapter.IsOnCurve(new(big.Int).Sub(x1, p256.Params().P), y1))
+
+		ax, ay := adapter.ScalarBaseMult(k1)
+		assert.Equal(t, x1.String()+","+y1.String(), ax.String()+","+ay.String())
+		gx, gy := p256.Add(x1, y1, x2, y2)
+		ax, ay = adapter.Add(x1, y1, x2, y2)
+		assert.Equal(t, gx.String()+","+gy.String(), ax.String()+","+ay.String())
+		gx, gy = p256.Double(x1, y1)
+		ax, ay = adapter.Double(x1, y1)
+		assert.Equal(t, gx.String()+","+gy.String(), ax.String()+","+ay.String())
+		gx, gy = p256.ScalarMult(x1, y1, k2)
+		ax, ay = adapter.ScalarMult(x1, y1, k2)
+		assert.Equal(t, gx.String()+","+gy.String(), ax.String()+","+ay.String())
+	}
+}
+
+// TestCurveAdapterScalarBaseMult checks ScalarBaseMult against golang's P-256 and that it uses the ladder.
+func TestCurveAdapterScalarBaseMult(t *testing.T) {
+	p256 := elliptic.P256()
+	curve := P256()
+	adapter := NewCurveAdapter(curve)
+	nMinus1 := new(big.Int).Sub(curve.N, big.NewInt(1))
+	scalars := [][]byte{{1}, {2}, {0, 0, 7}, nMinus1.Bytes()}
+	for i := 0; i < 5; i++ {
+		k := make([]byte, 32)
+		_, _ = rand.Read(k)
+		scalars = append(scalars, k)
+	}
+	for _, k := range scalars {
+		gx, gy := p256.ScalarBaseMult(k)
+		ax, ay := adapter.ScalarBaseMult(k)
+		assert.Equal(t, gx.String()+","+gy.String(), ax.String()+","+ay.String())
+	}
+	// the ladder does one addition and one doubling per bit of N, even for k=1
+	curve.Stats = &Stats{}
+	adapter.ScalarBaseMult([]byte{1})
+	assert.Equal(t, uint64(256), curve.Stats.Snapshot().Additions)
+}
+
+// TestCurveAdapterInfinity checks that the point at infinity goes in and out as (0,0).
+func TestCurveAdapterInfinity(t *testing.T) {
+	curve := newSmallCurve(14, 19, 3623)
+	curve.G, curve.N = pt(6, 730), big.NewInt(3566)
+	adapter := NewCurveAdapter(curve)
+	zero := new(big.Int)
+
+	x, y := adapter.Add(big.NewInt(6), big.NewInt(730), big.NewInt(6), big.NewInt(3623-730))
+	assert.Equal(t, 0, x.Sign())
+	assert.Equal(t, 0, y.Sign())
+	x, y = adapter.Add(zero, zero, big.NewInt(6), big.NewInt(730))
+	assert.True(t, curve.Equal(&Point{X: x, Y: y}, pt(6, 730)))
+	x, y = adapter.Double(big.NewInt(802), zero)
+	assert.Equal(t, 0, x.Sign())
+	assert.Equal(t, 0, y.Sign())
+	x, y = adapter.ScalarBaseMult(big.NewInt(3566).Bytes())
+	assert.Equal(t, 0, x.Sign())
+	assert.Equal(t, 0, y.Sign())
+	x, y = adapter.ScalarMult(zero, zero, []byte{5})
+	assert.Equal(t, 0, x.Sign())
+	assert.Equal(t, 0, y.Sign())
+}
+
+// TestGenerateKeyAdapter runs golang's GenerateKey on a curve with A=14 through the adapter.
+func TestGenerateKeyAdapter(t *testing.T) {
+	curve := newSmallCurve(14, 19, 3623)
+	curve.G, curve.N = pt(6, 730), big.NewInt(3566)
+	priv, x, y, err := elliptic.GenerateKey(NewCurveAdapter(curve), rand.Reader)
+	assert.NoError(t, err)
+	pub := &Point{X: x, Y: y}
+	assert.True(t, curve.IsOnCurve(pub))
+	assert.True(t, curve.Equal(pub, curve.ScalarMult(priv, curve.G)))
+}
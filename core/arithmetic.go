@@ -3,26 +3,38 @@ package core
 import (
 	"crypto/elliptic"
 	"crypto/rand"
+	"ecc/field"
 	"fmt"
 	"math/big"
 	"strconv"
+	"sync"
 )
 
 /*
 TODO:
-1. Make sure points are on curve before operations, otherwise vulnerable to attack.
-2. Both ScalarMult and ScalarMultTernary is vulnerable to timing attack. Implement constant time mult.
-3. Implement more efficient way to convert to NAF (Non-Adjacent Form).
+1. The operations here don't check that points are on the curve, use the checked ones in checked.go for untrusted points.
+2. ScalarMult, ScalarMultNAF and ScalarMultWNAF are vulnerable to timing attack, use ScalarMultLadder for secret scalars. The ladder does the same operations for every scalar, but the field package and the final conversion to big.Int are not audited to be constant time.
 */
 
 /*
 Point represents a point on the elliptic curve. It uses pointer to big.Int instead of the value itself because the API for big.Int is mostly built on pointers.
 
-The point at infinity \mathcal{0} is represented by (0,0) even though the point (0,0) itself might not be on the curve. This is because I lack a way to represent it nicely so that I can do comparison and allocation. Note that the Add method will return P if given (0,0)+P or P+(0,0).
+The point at infinity \mathcal{0} is represented by setting the Infinity flag, in which case X and Y carry no meaning. Use Infinity() to create one so that X and Y are still valid big.Int and printing or copying the point doesn't blow up. Note that (0,0) is an ordinary point, and it is actually on the curve whenever B=0.
 */
 type Point struct {
-	X *big.Int
-	Y *big.Int
+	X        *big.Int
+	Y        *big.Int
+	Infinity bool // true if this is the point at infinity, X and Y are ignored
+}
+
+// Infinity returns a new point at infinity, the identity of the group.
+func Infinity() *Point {
+	return &Point{X: new(big.Int), Y: new(big.Int), Infinity: true}
+}
+
+// copyPoint returns a deep copy of p so that callers can't change p through the result.
+func copyPoint(p *Point) *Point {
+	return &Point{X: new(big.Int).Set(p.X), Y: new(big.Int).Set(p.Y), Infinity: p.Infinity}
 }
 
 /*
@@ -33,15 +45,30 @@ A and B.
 type EllipticCurve struct {
 	P       *big.Int // order of the underlying field
 	N       *big.Int // order of the base point
+	H       *big.Int // cofactor, the number of points is H*N, nil if unknown
 	A, B    *big.Int // constants of the curve equation
 	G       *Point   // base point
 	BitSize int      // size of the underlying field in bits
 	Name    string   // name of the curve
+	Stats   *Stats   // operation counters, nil turns counting off, see stats.go
+
+	setupOnce sync.Once     // guards fp, a and shape, see setup()
+	fp        *field.Field  // F_p
+	a         field.Element // A in F_p
+	shape     aShape        // special form of A
+
+	baseOnce       sync.Once          // guards baseTableCache
+	baseTableCache [][]*jacobianPoint // multiples of G, see baseTable()
 }
 
-/* Equal returns true if two points are equal on the curve.
- */
+/*
+Equal returns true if two points are equal on the curve.
+Two points at infinity are equal regardless of their coordinates.
+*/
 func (curve *EllipticCurve) Equal(p1, p2 *Point) bool {
+	if p1.Infinity || p2.Infinity {
+		return p1.Infinity == p2.Infinity
+	}
 	if p1.X.Cmp(p2.X) == 0 && p1.Y.Cmp(p2.Y) == 0 {
 		return true
 	} else {
@@ -49,26 +76,22 @@ func (curve *EllipticCurve) Equal(p1, p2 *Point) bool {
 	}
 }
 
-/* EqualPointAtInfinity returns true if the point is equal to the point at infinity, irrespective of what we finally choose to be the representation of point at infinity. For now, it's (0,0).
+/* EqualPointAtInfinity returns true if the point is equal to the point at infinity, irrespective of what we finally choose to be the representation of point at infinity. For now, it's the Infinity flag on Point.
  */
 func (curve *EllipticCurve) EqualPointAtInfinity(p *Point) bool {
-	// to test if a big.Int is zero, it's apparently faster to
-	// check the length of its bits
-	// https://stackoverflow.com/questions/64257065/is-there-another-way-of-testing-if-a-big-int-is-0
-	if len(p.X.Bits()) == 0 && len(p.Y.Bits()) == 0 {
-		return true
-	} else {
-		return false
-	}
+	return p.Infinity
 }
 
 /*
 IsOnCurve returns true if the point is on the curve.
 Calculate x^3-Ax+B and compare it with y^2.
-It will return true if p is (0,0), even if the point is not technically on the curve.
+It will return true if p is the point at infinity, since it belongs to the group.
 This is useful for detecting illegal curve operations where the point is not even on the curve.
 */
 func (curve *EllipticCurve) IsOnCurve(p *Point) bool {
+	if p.Infinity {
+		return true
+	}
 	x3 := new(big.Int).Mul(p.X, p.X)
 	x3.Mul(x3, p.X)
 	xA := new(big.Int).Set(curve.A)
@@ -86,40 +109,53 @@ func (curve *EllipticCurve) IsOnCurve(p *Point) bool {
 Add two points on the curve, return a new point.
 This uses the very naive approach of calculating the slope of the line going
 through the two points.
-If either of the point is (0,0), return the other point.
+If either of the point is the point at infinity, return the other point.
+If the x coordinates differ, use the chord through the two points. Otherwise the points are either P and -P, or the same point P. P+(-P) returns the point at infinity, and so does doubling a point with y=0 (a 2-torsion point) since its tangent is vertical. Both cases are caught by checking y1+y2=0 mod p.
 NOTE: the points passed to Add are supposed to be on the curve, this will still work even if one of the points is not on the curve. Checking has to be done elsewhere.
-TODO: handle cases: (1) both is 0 (2)they add up to 0.
 */
 func (curve *EllipticCurve) Add(p1, p2 *Point) *Point {
-	// if p1=(0,0), return a copy of p2
+	// if p1=O, return a copy of p2
 	if curve.EqualPointAtInfinity(p1) {
-		return &Point{new(big.Int).Set(p2.X), new(big.Int).Set(p2.Y)}
+		return copyPoint(p2)
 	}
-	// if p2=(0,0), return a copy of p1
+	// if p2=O, return a copy of p1
 	if curve.EqualPointAtInfinity(p2) {
-		return &Point{new(big.Int).Set(p1.X), new(big.Int).Set(p1.Y)}
+		return copyPoint(p1)
 	}
-	// otherwise neither of the point is (0,0)
+	// otherwise neither of the point is O
 	lambda := new(big.Int)
 	top := new(big.Int)
 	bottom := new(big.Int)
-	if p1.X.Cmp(p2.X) != 0 && p1.Y.Cmp(p2.Y) != 0 {
+	bottom.Sub(p2.X, p1.X)
+	bottom.Mod(bottom, curve.P)
+	if len(bottom.Bits()) != 0 { // x1 != x2, chord
+		curve.count(opAdd)
 		top.Sub(p2.Y, p1.Y)
-		bottom.Sub(p2.X, p1.X)
 		bottom.ModInverse(bottom, curve.P)
+		curve.count(opInverse)
 		lambda.Mul(top, bottom)
 		lambda.Mod(lambda, curve.P)
-	} else if curve.Equal(p1, p2) {
+	} else {
+		// x1 == x2, so either y1 == -y2 or the points are equal
+		top.Add(p1.Y, p2.Y)
+		top.Mod(top, curve.P)
+		if len(top.Bits()) == 0 { // vertical line, P+(-P) or 2P with y=0
+			curve.count(opAdd)
+			return Infinity()
+		}
+		// tangent
+		curve.count(opDouble)
 		top.Mul(p1.X, p1.X)
 		top.Mul(top, big.NewInt(3))
 		top.Add(top, curve.A)
 		top.Mod(top, curve.P)
 		bottom.Mul(p1.Y, big.NewInt(2))
 		bottom.ModInverse(bottom, curve.P)
+		curve.count(opInverse)
 		lambda.Mul(top, bottom)
 		lambda.Mod(lambda, curve.P)
 	}
-	p := &Point{new(big.Int), new(big.Int)}
+	p := &Point{X: new(big.Int), Y: new(big.Int)}
 	p.X.Mul(lambda, lambda)
 	p.X.Sub(p.X, p1.X)
 	p.X.Sub(p.X, p2.X)
@@ -132,20 +168,28 @@ func (curve *EllipticCurve) Add(p1, p2 *Point) *Point {
 }
 
 /*
-Negate the point on the curve, i.e. return a point with -y. This should not change the point p.
+Negate the point on the curve, i.e. return a point with -y mod p. This should not change the point p.
 This function exists because negation of big.Int is fiddly and change its value.
+The negation of the point at infinity is itself.
 */
 func (curve *EllipticCurve) Negate(p *Point) *Point {
 	// make a copy of p so that we don't change it
-	p1 := &Point{new(big.Int).Set(p.X), new(big.Int).Set(p.Y)}
+	p1 := copyPoint(p)
+	if p1.Infinity {
+		return p1
+	}
 	p1.Y.Neg(p.Y)
+	p1.Y.Mod(p1.Y, curve.P)
 	return p1
 }
 
 /*
 Use the double and add method to calculate nP. Essentially, write n in binary
-form, doubling P as we go along the bitstring of n, adding the result
-when we see a 1 bit. See Hoffstein 6.3.1.
+form, going along the bitstring of n from the most significant bit, doubling the
+result at every bit and adding P when we see a 1 bit. See Hoffstein 6.3.1.
+
+The computation is done in Jacobian coordinates so that there's only one
+inversion at the very end, see jacobian.go.
 
 Note that this is vulnerable to timing analysis:
 https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication
@@ -153,93 +197,100 @@ https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication
 Generally, the size of n is going to be the order of the base point, so we use []byte for n. It's likely to be randomly generated by reading from rand.
 */
 func (curve *EllipticCurve) ScalarMult(n []byte, p *Point) *Point {
-	// converting n into big.Int because we need to right shift
-	// TODO (later) write function to shift byte slice so that
-	// we don't have to convert n to big.Int
+	// converting n into big.Int so that we can read it bit by bit
 	scalar := new(big.Int).SetBytes(n)
-	ret := &Point{big.NewInt(0), big.NewInt(0)}
-	doubles := &Point{new(big.Int).Set(p.X), new(big.Int).Set(p.Y)}
-	additions := 0
-	// carry on computation until n is 0
-	for len(scalar.Bits()) != 0 {
-		if scalar.Bit(0) == 1 { // time to add things up
-			ret = curve.Add(ret, doubles)
-			additions++
+	ret := jacobianInfinity()
+	jp := curve.toJacobian(p)
+	for i := scalar.BitLen() - 1; i >= 0; i-- {
+		ret = curve.jacobianDouble(ret)
+		if scalar.Bit(i) == 1 { // time to add things up
+			ret = curve.jacobianAdd(ret, jp)
 		}
-		doubles = curve.Add(doubles, doubles)
-		scalar.Rsh(scalar, 1)
 	}
-	// the first addtion P+(0,0) doesn't count
-	fmt.Println("Total number of additions:", additions-1)
-	return ret
+	return curve.toAffine(ret)
 }
 
 /*
-ScalarMultTernary uses the ternary expansion of n.
-First preprocess the bits of n to get rid of contiguous groups of 1s with size > 2.
-Then use double and add/subtract to calculate nP.
+ScalarMultNAF uses the non-adjacent form (NAF) of n, see naf.go. The digits of the NAF are
+0, 1 and -1 and there are no adjacent non-zero digits, so only about 1/3 of the digits need an
+addition, compared to 1/2 for double and add.
+Then use double and add/subtract to calculate nP, in Jacobian coordinates just like ScalarMult.
 */
-func (curve *EllipticCurve) ScalarMultTernary(n []byte, p *Point) *Point {
-	trits := preprocessTrits(n)
-	ret := &Point{big.NewInt(0), big.NewInt(0)}
-	doubles := &Point{new(big.Int).Set(p.X), new(big.Int).Set(p.Y)}
-	additions := 0
-	for i := 0; i < len(trits); i++ {
-		if trits[i] == 1 {
-			ret = curve.Add(ret, doubles)
-			additions++
+func (curve *EllipticCurve) ScalarMultNAF(n []byte, p *Point) *Point {
+	digits := naf(new(big.Int).SetBytes(n))
+	ret := jacobianInfinity()
+	jp := curve.toJacobian(p)
+	jpNeg := curve.jacobianNegate(jp)
+	// digits has the lowest digit to the left, so go through it backwards
+	for i := len(digits) - 1; i >= 0; i-- {
+		ret = curve.jacobianDouble(ret)
+		if digits[i] == 1 {
+			ret = curve.jacobianAdd(ret, jp)
 		}
-		if trits[i] == -1 {
-			ret = curve.Add(ret, curve.Negate(doubles))
-			additions++
+		if digits[i] == -1 {
+			ret = curve.jacobianAdd(ret, jpNeg)
 		}
-		doubles = curve.Add(doubles, doubles)
 	}
-	// the first addtion P+(0,0) doesn't count
-	fmt.Println("Total number of additions:", additions-1)
-	return ret
+	return curve.toAffine(ret)
 }
 
 /*
-preprocessTrits turns n into a ternary array, using 0, 1 and -1. The result should have no contiguous group of 1s with length>2 ("bad group").
+ScalarMultWNAF uses the width-w NAF of n, see naf.go. The non-zero digits are odd and less than
+2^(w-1) in absolute value, so we precompute P, 3P, ..., (2^(w-1)-1)P, which costs 2^(w-2)
+additions, and in return only about 1/(w+1) of the digits need an addition.
+w has to be between 2 and 8, w=2 is the same as ScalarMultNAF. For 256-bit scalars, w=4 or w=5 is
+usually the sweet spot.
 */
-func preprocessTrits(n []byte) []int8 {
-	// unpack n into bits so that the lowest bit is to the left
-	trits := make([]int8, len(n)*8)
-	bitLen := len(n)*8 - 1
-	for i, b := range n {
-		for j := 0; j < 8; j++ {
-			trits[bitLen-(i*8+j)] = int8(b >> uint(7-j) & 1)
+func (curve *EllipticCurve) ScalarMultWNAF(n []byte, p *Point, w uint) *Point {
+	digits := wnaf(new(big.Int).SetBytes(n), w)
+	table := curve.oddMultiples(curve.toJacobian(p), w)
+	ret := jacobianInfinity()
+	for i := len(digits) - 1; i >= 0; i-- {
+		ret = curve.jacobianDouble(ret)
+		if d := digits[i]; d > 0 {
+			ret = curve.jacobianAdd(ret, table[d/2])
+		} else if d < 0 {
+			ret = curve.jacobianAdd(ret, curve.jacobianNegate(table[-d/2]))
 		}
 	}
-	// process bits to get rid of bad groups, s marks the start of a group
-	// t marks the number of 1s in the group
-	s, t := 0, 0
-	for i := 0; i < len(trits); i++ {
-		if trits[i] == 1 {
-			if i >= 1 && trits[i-1] == 0 { // prev trit is 0
-				s = i // mark the start of the group
-			}
-			t++
-		} else if trits[i] == 0 {
-			if t > 2 { // at the end of a bad group
-				// turn start of the group into -1
-				trits[s] = -1
-				// flip the rest of the group to 0
-				for j := s + 1; j < s+t; j++ {
-					trits[j] = 0
-				}
-				// flip current bit (should be 0) to 1
-				trits[i] = 1
-				// mark the start of a new group
-				s = i
-				t = 1
-			} else { // otherwise it wasn't a bad group, ignore
-				t = 0 // reset group length
-			}
-		}
+	return curve.toAffine(ret)
+}
+
+/*
+ScalarMultLadder calculates nP using the Montgomery ladder. It keeps two points R0 and R1 with
+the invariant R1-R0=P, and for every bit of n does exactly one addition and one doubling:
+if the bit is 0, R1=R0+R1 and R0=2R0, otherwise R0=R0+R1 and R1=2R1.
+Instead of branching on the bit, the points are conditionally swapped before and after each step,
+so the sequence of operations doesn't depend on the bits of n.
+
+The number of steps doesn't depend on n either: n is padded to the bit length of N, the order of
+the base point (or of p+1 if N isn't set, which bounds the order of any point by Hasse),
+or to len(n)*8 if n is longer than that. The steps use ladderAdd and ladderDouble, which don't
+take shortcuts while R0 is still the point at infinity, so leading zero bits cost as much as any
+other bit.
+
+NOTE: this removes the branches on secret bits and makes the work independent of n, but the
+limb arithmetic of the field package isn't audited to be constant time, so don't count on this
+against a determined timing attack either.
+*/
+func (curve *EllipticCurve) ScalarMultLadder(n []byte, p *Point) *Point {
+	scalar := new(big.Int).SetBytes(n)
+	bits := len(n) * 8
+	if curve.N != nil && curve.N.BitLen() > bits {
+		bits = curve.N.BitLen()
+	} else if curve.N == nil && curve.P.BitLen()+1 > bits {
+		bits = curve.P.BitLen() + 1
+	}
+	r0 := jacobianInfinity()
+	r1 := curve.toJacobian(p)
+	for i := bits - 1; i >= 0; i-- {
+		b := scalar.Bit(i)
+		r0, r1 = conditionalSwap(b, r0, r1)
+		r1 = curve.ladderAdd(r0, r1)
+		r0 = curve.ladderDouble(r0)
+		r0, r1 = conditionalSwap(b, r0, r1)
 	}
-	return trits
+	return curve.toAffine(r0)
 }
 
 func Example() {
@@ -249,17 +300,21 @@ func Example() {
 	curve.A = big.NewInt(14)
 	curve.B = big.NewInt(19)
 	curve.P = big.NewInt(3623)
-	fmt.Println(curve)
+	curve.Stats = &Stats{}
+	fmt.Println(curve.Name)
 	p := &Point{X: big.NewInt(6), Y: big.NewInt(730)}
 	n, _ := strconv.ParseInt("100110111001", 2, 32) // 2489
 
 	/////////// Small example: Scalar mult with double and add
 	np := curve.ScalarMult(big.NewInt(n).Bytes(), p)
 	fmt.Println(np, "ScalarMult with Double and Add")
+	fmt.Println(curve.Stats)
 
-	/////////// Small example: Scalar mult with ternary expansion
-	npTernary := curve.ScalarMultTernary(big.NewInt(n).Bytes(), p)
-	fmt.Println(npTernary, "ScarlarMult with Ternary Expansion")
+	/////////// Small example: Scalar mult with NAF
+	curve.Stats.Reset()
+	npNAF := curve.ScalarMultNAF(big.NewInt(n).Bytes(), p)
+	fmt.Println(npNAF, "ScalarMult with NAF")
+	fmt.Println(curve.Stats)
 
 	// setup industrial size curve p256, and point and n
 	// set up golang's curve and points
@@ -273,35 +328,20 @@ func Example() {
 
 	// setup our curve
 	curve = P256()
-	p = &Point{pX, pY}
+	curve.Stats = &Stats{}
+	p = &Point{X: pX, Y: pY}
 	fmt.Println("ScalarMult with Double and Add")
 	np = curve.ScalarMult(b, p)
 	fmt.Println("ScalarMult correct?", np.X.Cmp(npX) == 0 && np.Y.Cmp(npY) == 0)
-	fmt.Println("ScalarMult with Ternary Expansion")
-	npTernary = curve.ScalarMultTernary(b, p)
-	fmt.Println("ScalarMultTernary correct?", np.X.Cmp(npX) == 0 && np.Y.Cmp(npY) == 0)
-}
-
-/*
-Returns a curve that uses golang p256 curve parameters, which is based on FIPS 186-3, section D.2.3 (p.100). Per this guide, section D.1.2 specifies the curve equation:
-$$E:y^2 \equiv x^3-3x+b (mod p)$$
-
-Golang's implementation:
-https://cs.opensource.google/go/go/+/refs/tags/go1.16.6:src/crypto/elliptic/p256.go
-
-NIST publication:
-https://csrc.nist.gov/csrc/media/publications/fips/186/3/archive/2009-06-25/documents/fips_186-3.pdf
-*/
-func P256() *EllipticCurve {
-	curve := &EllipticCurve{Name: "P-256"}
-	curve.A = big.NewInt(-3)
-	curve.B, _ = new(big.Int).SetString("5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b", 16)
-	curve.P, _ = new(big.Int).SetString("115792089210356248762697446949407573530086143415290314195533631308867097853951", 10)
-	curve.N, _ = new(big.Int).SetString("115792089210356248762697446949407573529996955224135760342422259061068512044369", 10)
-	p := &Point{}
-	p.X, _ = new(big.Int).SetString("6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296", 16)
-	p.Y, _ = new(big.Int).SetString("4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5", 16)
-	curve.G = p
-	curve.BitSize = 256
-	return curve
+	fmt.Println(curve.Stats)
+	fmt.Println("ScalarMult with NAF")
+	curve.Stats.Reset()
+	npNAF = curve.ScalarMultNAF(b, p)
+	fmt.Println("ScalarMultNAF correct?", npNAF.X.Cmp(npX) == 0 && npNAF.Y.Cmp(npY) == 0)
+	fmt.Println(curve.Stats)
+	fmt.Println("ScalarMult with width-5 NAF")
+	curve.Stats.Reset()
+	npWNAF := curve.ScalarMultWNAF(b, p, 5)
+	fmt.Println("ScalarMultWNAF correct?", npWNAF.X.Cmp(npX) == 0 && npWNAF.Y.Cmp(npY) == 0)
+	fmt.Println(curve.Stats)
 }
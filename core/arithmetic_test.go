@@ -16,18 +16,18 @@ func TestAdd(t *testing.T) {
 	curve.A = big.NewInt(3)
 	curve.B = big.NewInt(8)
 	curve.P = big.NewInt(13)
-	p1 := &Point{big.NewInt(9), big.NewInt(7)}
-	p2 := &Point{big.NewInt(1), big.NewInt(8)}
-	zero := &Point{big.NewInt(0), big.NewInt(0)}
+	p1 := &Point{X: big.NewInt(9), Y: big.NewInt(7)}
+	p2 := &Point{X: big.NewInt(1), Y: big.NewInt(8)}
+	zero := Infinity()
 	// test 1: P1 + P2
 	p3 := curve.Add(p1, p2)
-	assert.True(t, curve.Equal(p3, &Point{big.NewInt(2), big.NewInt(10)}))
+	assert.True(t, curve.Equal(p3, &Point{X: big.NewInt(2), Y: big.NewInt(10)}))
 	// test 2: P1 + P1
 	p3 = curve.Add(p1, p1)
-	assert.True(t, curve.Equal(p3, &Point{big.NewInt(9), big.NewInt(6)}))
+	assert.True(t, curve.Equal(p3, &Point{X: big.NewInt(9), Y: big.NewInt(6)}))
 	// test 3: P2 + P2
 	p3 = curve.Add(p2, p2)
-	assert.True(t, curve.Equal(p3, &Point{big.NewInt(2), big.NewInt(3)}))
+	assert.True(t, curve.Equal(p3, &Point{X: big.NewInt(2), Y: big.NewInt(3)}))
 	// test 4: P1 + 0 = P1
 	p3 = curve.Add(p1, zero)
 	assert.True(t, curve.Equal(p3, p1))
@@ -39,7 +39,110 @@ func TestAdd(t *testing.T) {
 	assert.False(t, curve.Equal(p3, zero))
 	// test 6: P1-P2
 	p3 = curve.Add(p1, curve.Negate(p2))
-	assert.True(t, curve.Equal(p3, &Point{big.NewInt(12), big.NewInt(2)}))
+	assert.True(t, curve.Equal(p3, &Point{X: big.NewInt(12), Y: big.NewInt(2)}))
+}
+
+// newSmallCurve returns a toy curve y^2=x^3+ax+b over F_p for testing.
+func newSmallCurve(a, b, p int64) *EllipticCurve {
+	curve := &EllipticCurve{Name: fmt.Sprintf("y^2=x^3+%dx+%d mod %d", a, b, p)}
+	curve.A = big.NewInt(a)
+	curve.B = big.NewInt(b)
+	curve.P = big.NewInt(p)
+	return curve
+}
+
+// pt is a shorthand for an affine point with small coordinates.
+func pt(x, y int64) *Point {
+	return &Point{X: big.NewInt(x), Y: big.NewInt(y)}
+}
+
+// smallCurves are every toy curve used in this file.
+var smallCurves = []*EllipticCurve{
+	newSmallCurve(3, 8, 13),
+	newSmallCurve(14, 19, 3623),
+	newSmallCurve(1, 0, 23),
+	newSmallCurve(-3, 5, 29),
+	newSmallCurve(0, 7, 31),
+}
+
+func TestAddGroupLaw(t *testing.T) {
+	tests := []struct {
+		name   string
+		curve  *EllipticCurve
+		p1, p2 *Point
+		want   *Point
+	}{
+		{"chord", smallCurves[0], pt(9, 7), pt(1, 8), pt(2, 10)},
+		{"double", smallCurves[0], pt(9, 7), pt(9, 7), pt(9, 6)},
+		{"P+(-P)", smallCurves[0], pt(9, 7), pt(9, 6), Infinity()},
+		{"P+O", smallCurves[0], pt(1, 8), Infinity(), pt(1, 8)},
+		{"O+O", smallCurves[0], Infinity(), Infinity(), Infinity()},
+		{"chord", smallCurves[1], pt(6, 730), pt(802, 0), pt(954, 1960)},
+		{"double", smallCurves[1], pt(6, 730), pt(6, 730), pt(2521, 3601)},
+		{"same y, different x", smallCurves[1], pt(0, 351), pt(1695, 351), pt(1928, 3272)},
+		{"P+(-P)", smallCurves[1], pt(6, 730), pt(6, 3623-730), Infinity()},
+		{"2-torsion", smallCurves[1], pt(802, 0), pt(802, 0), Infinity()},
+		{"chord", smallCurves[2], pt(9, 5), pt(11, 10), pt(15, 3)},
+		{"same y, different x", smallCurves[2], pt(1, 5), pt(9, 5), pt(13, 18)},
+		{"P+(-P)", smallCurves[2], pt(1, 5), pt(1, 18), Infinity()},
+		{"2-torsion", smallCurves[2], pt(0, 0), pt(0, 0), Infinity()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.curve.Name+"/"+tt.name, func(t *testing.T) {
+			assert.True(t, tt.curve.IsOnCurve(tt.p1))
+			assert.True(t, tt.curve.IsOnCurve(tt.p2))
+			assert.True(t, tt.curve.Equal(tt.curve.Add(tt.p1, tt.p2), tt.want))
+			assert.True(t, tt.curve.Equal(tt.curve.Add(tt.p2, tt.p1), tt.want))
+		})
+	}
+}
+
+/*
+TestAddSmallCurves checks the group law on every point of the small curves: the sum stays on the
+curve, P+(-P)=O, addition commutes and associates.
+The curve over F_3623 is too large to enumerate, so only the curves with p < 100 are checked.
+*/
+func TestAddSmallCurves(t *testing.T) {
+	for _, curve := range smallCurves {
+		if curve.P.Int64() >= 100 {
+			continue
+		}
+		points := smallCurvePoints(curve)
+		for _, p1 := range points {
+			assert.True(t, curve.EqualPointAtInfinity(curve.Add(p1, curve.Negate(p1))), curve.Name)
+			for _, p2 := range points {
+				sum := curve.Add(p1, p2)
+				assert.True(t, curve.IsOnCurve(sum), curve.Name)
+				assert.True(t, curve.Equal(sum, curve.Add(p2, p1)), curve.Name)
+				for _, p3 := range points {
+					assert.True(t, curve.Equal(curve.Add(sum, p3), curve.Add(p1, curve.Add(p2, p3))), curve.Name)
+				}
+			}
+		}
+	}
+}
+
+/*
+On a curve with B=0 the point (0,0) is a genuine point of order 2, so it must not be
+mistaken for the point at infinity.
+*/
+func TestPointAtInfinity(t *testing.T) {
+	curve := smallCurves[2]
+	zero := &Point{X: big.NewInt(0), Y: big.NewInt(0)}
+	inf := Infinity()
+	p := &Point{X: big.NewInt(1), Y: big.NewInt(5)}
+	assert.True(t, curve.IsOnCurve(zero))
+	assert.True(t, curve.IsOnCurve(inf))
+	assert.False(t, curve.Equal(zero, inf))
+	assert.True(t, curve.Equal(inf, Infinity()))
+	assert.True(t, curve.Equal(curve.Negate(inf), inf))
+	// (0,0) + P is a chord, not the identity
+	assert.True(t, curve.Equal(curve.Add(zero, p), &Point{X: big.NewInt(1), Y: big.NewInt(18)}))
+	assert.True(t, curve.Equal(curve.Add(p, inf), p))
+	// P has order 4 and 2P=(0,0) shows up in the middle of the computation
+	assert.True(t, curve.Equal(curve.ScalarMult(big.NewInt(2).Bytes(), p), zero))
+	assert.True(t, curve.Equal(curve.ScalarMult(big.NewInt(3).Bytes(), p), &Point{X: big.NewInt(1), Y: big.NewInt(18)}))
+	assert.True(t, curve.Equal(curve.ScalarMultNAF(big.NewInt(3).Bytes(), p), &Point{X: big.NewInt(1), Y: big.NewInt(18)}))
 }
 
 /*
@@ -65,10 +168,10 @@ func TestAddLarge(t *testing.T) {
 
 	// setup our curve
 	curve := P256()
-	p1 := &Point{p1X, p1Y}
-	p2 := &Point{p2X, p2Y}
+	p1 := &Point{X: p1X, Y: p1Y}
+	p2 := &Point{X: p2X, Y: p2Y}
 	p3 := curve.Add(p1, p2)
-	assert.True(t, curve.Equal(p3, &Point{p3X, p3Y}))
+	assert.True(t, curve.Equal(p3, &Point{X: p3X, Y: p3Y}))
 }
 
 func TestScalarMult(t *testing.T) {
@@ -81,7 +184,7 @@ func TestScalarMult(t *testing.T) {
 	n := big.NewInt(947)
 	p := &Point{X: big.NewInt(6), Y: big.NewInt(730)}
 	np := curve.ScalarMult(n.Bytes(), p)
-	assert.True(t, curve.Equal(np, &Point{big.NewInt(3492), big.NewInt(60)}))
+	assert.True(t, curve.Equal(np, &Point{X: big.NewInt(3492), Y: big.NewInt(60)}))
 }
 
 func TestScalarMultLarge(t *testing.T) {
@@ -95,22 +198,12 @@ func TestScalarMultLarge(t *testing.T) {
 
 	// setup our curve
 	curve := P256()
-	p := &Point{pX, pY}
+	p := &Point{X: pX, Y: pY}
 	np := curve.ScalarMult(b, p)
-	assert.True(t, curve.Equal(np, &Point{npX, npY}))
-}
-
-func TestPreprocessTrits(t *testing.T) {
-	n, _ := strconv.ParseInt("100110111001", 2, 32) // n=2489
-	trits := preprocessTrits(big.NewInt(n).Bytes())
-	assert.Equal(t, trits, []int8{1, 0, 0, -1, 0, 0, -1, 0, 0, 1, 0, 1, 0, 0, 0, 0})
-
-	n, _ = strconv.ParseInt("10011110111001", 2, 32) // n=10169
-	trits = preprocessTrits(big.NewInt(n).Bytes())
-	assert.Equal(t, trits, []int8{1, 0, 0, -1, 0, 0, -1, 0, 0, 0, 0, 1, 0, 1, 0, 0})
+	assert.True(t, curve.Equal(np, &Point{X: npX, Y: npY}))
 }
 
-func TestScalarMultTernary(t *testing.T) {
+func TestScalarMultNAF(t *testing.T) {
 	curve := &EllipticCurve{Name: "y^2=x^3+14x+19"}
 	curve.A = big.NewInt(14)
 	curve.B = big.NewInt(19)
@@ -118,12 +211,16 @@ func TestScalarMultTernary(t *testing.T) {
 	p := &Point{X: big.NewInt(6), Y: big.NewInt(730)}
 	n, _ := strconv.ParseInt("100110111001", 2, 32) // 2489
 	np := curve.ScalarMult(big.NewInt(2489).Bytes(), p)
-	npTernary := curve.ScalarMultTernary(big.NewInt(n).Bytes(), p)
-	assert.True(t, curve.Equal(np, &Point{big.NewInt(3241), big.NewInt(2032)}))
-	assert.True(t, curve.Equal(npTernary, &Point{big.NewInt(3241), big.NewInt(2032)}))
+	npNAF := curve.ScalarMultNAF(big.NewInt(n).Bytes(), p)
+	assert.True(t, curve.Equal(np, &Point{X: big.NewInt(3241), Y: big.NewInt(2032)}))
+	assert.True(t, curve.Equal(npNAF, &Point{X: big.NewInt(3241), Y: big.NewInt(2032)}))
+	for w := uint(2); w <= 8; w++ {
+		npWNAF := curve.ScalarMultWNAF(big.NewInt(n).Bytes(), p, w)
+		assert.True(t, curve.Equal(npWNAF, &Point{X: big.NewInt(3241), Y: big.NewInt(2032)}), "w=%d", w)
+	}
 }
 
-func TestScalarMultTernaryLarge(t *testing.T) {
+func TestScalarMultNAFLarge(t *testing.T) {
 	// set up golang's curve and points
 	p256 := elliptic.P256()
 	b := make([]byte, 32)
@@ -134,11 +231,56 @@ func TestScalarMultTernaryLarge(t *testing.T) {
 
 	// setup our curve
 	curve := P256()
-	p := &Point{pX, pY}
+	p := &Point{X: pX, Y: pY}
 	np := curve.ScalarMult(b, p)
-	assert.True(t, curve.Equal(np, &Point{npX, npY}))
-	npTernary := curve.ScalarMultTernary(b, p)
-	assert.True(t, curve.Equal(npTernary, &Point{npX, npY}))
+	assert.True(t, curve.Equal(np, &Point{X: npX, Y: npY}))
+	npNAF := curve.ScalarMultNAF(b, p)
+	assert.True(t, curve.Equal(npNAF, &Point{X: npX, Y: npY}))
+	for w := uint(2); w <= 8; w++ {
+		npWNAF := curve.ScalarMultWNAF(b, p, w)
+		assert.True(t, curve.Equal(npWNAF, &Point{X: npX, Y: npY}), "w=%d", w)
+	}
+}
+
+func TestScalarMultLadder(t *testing.T) {
+	// Hoffstein example 6.16 again, 947P
+	curve := smallCurves[1]
+	p := pt(6, 730)
+	np := curve.ScalarMultLadder(big.NewInt(947).Bytes(), p)
+	assert.True(t, curve.Equal(np, pt(3492, 60)))
+	// leading zero bytes and zero scalar
+	np = curve.ScalarMultLadder([]byte{0, 0, 3, 179}, p)
+	assert.True(t, curve.Equal(np, pt(3492, 60)))
+	assert.True(t, curve.ScalarMultLadder([]byte{0}, p).Infinity)
+	assert.True(t, curve.ScalarMultLadder(big.NewInt(947).Bytes(), Infinity()).Infinity)
+
+	// compare with double and add on every small point and small scalar
+	for _, curve := range smallCurves {
+		if curve.P.Int64() >= 100 {
+			continue
+		}
+		for _, p := range smallCurvePoints(curve) {
+			for n := int64(0); n < 2*curve.P.Int64(); n++ {
+				want := curve.ScalarMult(big.NewInt(n).Bytes(), p)
+				assert.True(t, curve.Equal(curve.ScalarMultLadder(big.NewInt(n).Bytes(), p), want), curve.Name)
+			}
+		}
+	}
+}
+
+func TestScalarMultLadderLarge(t *testing.T) {
+	p256 := elliptic.P256()
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	pX, pY := p256.ScalarBaseMult(b)
+	_, _ = rand.Read(b)
+	npX, npY := p256.ScalarMult(pX, pY, b)
+
+	curve := P256()
+	np := curve.ScalarMultLadder(b, &Point{X: pX, Y: pY})
+	assert.True(t, curve.Equal(np, &Point{X: npX, Y: npY}))
+	// N*G is the identity
+	assert.True(t, curve.ScalarMultLadder(curve.N.Bytes(), curve.G).Infinity)
 }
 
 func TestIsOnCurve(t *testing.T) {
@@ -153,3 +295,50 @@ func TestIsOnCurve(t *testing.T) {
 	p = &Point{X: big.NewInt(3241), Y: big.NewInt(2031)}
 	assert.False(t, curve.IsOnCurve(p))
 }
+
+func BenchmarkScalarMult(b *testing.B) {
+	curve := P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	for i := 0; i < b.N; i++ {
+		curve.ScalarMult(n, curve.G)
+	}
+}
+
+func BenchmarkScalarMultNAF(b *testing.B) {
+	curve := P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	for i := 0; i < b.N; i++ {
+		curve.ScalarMultNAF(n, curve.G)
+	}
+}
+
+func BenchmarkScalarMultWNAF(b *testing.B) {
+	curve := P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	for i := 0; i < b.N; i++ {
+		curve.ScalarMultWNAF(n, curve.G, 5)
+	}
+}
+
+func BenchmarkScalarMultLadder(b *testing.B) {
+	curve := P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	for i := 0; i < b.N; i++ {
+		curve.ScalarMultLadder(n, curve.G)
+	}
+}
+
+// BenchmarkGoScalarMult is the golang P-256 for comparison, it uses assembly on most platforms.
+func BenchmarkGoScalarMult(b *testing.B) {
+	p256 := elliptic.P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	gX, gY := p256.Params().Gx, p256.Params().Gy
+	for i := 0; i < b.N; i++ {
+		p256.ScalarMult(gX, gY, n)
+	}
+}
@@ -1,29 +1,48 @@
 package core
 
 import (
-	"crypto/elliptic"
-	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
 )
 
-/* KeyPair represents a public/private key pair for ECC
-The private key is a random number size equal to N, the order of the base point
+/*
+KeyPair represents a public/private key pair for ECC
+The private key is a random scalar mod N, the order of the base point, in [1, N-1].
 The public key is a point on the curve that's a multiple of the private key
 and the base point.
 */
 type KeyPair struct {
-	Priv []byte
+	Priv *Scalar
 	Pub  *Point
 }
 
-func GenerateKey(curve elliptic.Curve) (*KeyPair, error) {
-	// size of the private key is similar to size of the order of the base point
-	priv := make([]byte, curve.Params().N.BitLen()/8)
-	_, err := rand.Read(priv)
-	if err != nil {
+/*
+GenerateKey returns a new key pair for group, with randomness from rand, e.g. crypto/rand.Reader.
+The private key follows FIPS 186-4 B.4.1, key pair generation using extra random bits:
+take a random c of bitlen(N)+64 bits, then d = (c mod (N-1)) + 1. The 64 extra bits make the bias
+of the reduction negligible, about 2^-64, and the +1 makes sure that d is never 0.
+RandomScalar is the other option of FIPS 186-4, B.4.2 testing candidates.
+*/
+func GenerateKey(group Group, rand io.Reader) (*KeyPair, error) {
+	N := group.Order()
+	if N.Cmp(big.NewInt(2)) < 0 {
+		return nil, errors.New("core: order must be at least 2")
+	}
+	bitLen := N.BitLen() + 64
+	b := make([]byte, (bitLen+7)/8)
+	if _, err := io.ReadFull(rand, b); err != nil {
 		return nil, err
 	}
-	point := &Point{nil, nil}
-	point.X, point.Y = curve.ScalarBaseMult(priv)
-	pair := &KeyPair{priv, point}
-	return pair, nil
+	// keep exactly bitLen bits
+	if excess := uint(len(b)*8 - bitLen); excess > 0 {
+		b[0] &= 0xff >> excess
+	}
+	c := new(big.Int).SetBytes(b)
+	nMinus1 := new(big.Int).Sub(N, big.NewInt(1))
+	d := c.Mod(c, nMinus1)
+	d.Add(d, big.NewInt(1))
+	priv := &Scalar{n: N, v: d}
+	// d is secret, so not the faster ScalarBaseMult
+	return &KeyPair{Priv: priv, Pub: group.ScalarMultSecret(priv.Bytes(), group.Generator())}, nil
 }
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateKey(t *testing.T) {
+	// P-521 has an order of 521 bits, which is not a whole number of bytes
+	for _, g := range append(testGroups(), P521()) {
+		N := g.Order()
+		for i := 0; i < 5; i++ {
+			pair, err := GenerateKey(g, rand.Reader)
+			assert.NoError(t, err)
+			assert.False(t, pair.Priv.IsZero())
+			assert.Equal(t, -1, pair.Priv.BigInt().Cmp(N))
+			assert.Len(t, pair.Priv.Bytes(), (N.BitLen()+7)/8)
+			assert.True(t, g.Equal(pair.Pub, g.ScalarBaseMult(pair.Priv.Bytes())))
+		}
+	}
+}
+
+/*
+TestGenerateKeyFixedBytes feeds fixed bytes on the toy curve: N = 3566 has 12 bits, so GenerateKey
+reads 76 bits, i.e. 10 bytes with the top 4 bits cleared.
+*/
+func TestGenerateKeyFixedBytes(t *testing.T) {
+	g := testGroups()[1]
+	r := bytes.NewReader(make([]byte, 11))
+	pair, err := GenerateKey(g, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", pair.Priv.String(), "c = 0 gives d = 1")
+	assert.Equal(t, 1, r.Len(), "only 10 bytes are read")
+
+	pair, err = GenerateKey(g, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+	assert.NoError(t, err)
+	c := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 76), big.NewInt(1))
+	d := c.Mod(c, big.NewInt(3565))
+	assert.Equal(t, d.Add(d, big.NewInt(1)).String(), pair.Priv.String())
+
+	_, err = GenerateKey(g, bytes.NewReader(make([]byte, 9)))
+	assert.Error(t, err, "the reader ran out")
+
+	tiny := newSmallCurve(2, 3, 97)
+	tiny.G, tiny.N = pt(3, 6), big.NewInt(1)
+	_, err = GenerateKey(tiny, rand.Reader)
+	assert.Error(t, err)
+}
+
+/*
+TestGenerateKeyDistribution checks that every key is about as likely on a curve with a tiny order,
+where taking just the bytes of N would make some keys twice as likely as others.
+*/
+func TestGenerateKeyDistribution(t *testing.T) {
+	curve := newSmallCurve(2, 3, 97)
+	curve.G, curve.N = pt(3, 6), big.NewInt(5)
+	const n = 4000
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		pair, err := GenerateKey(curve, rand.Reader)
+		assert.NoError(t, err)
+		counts[pair.Priv.String()]++
+	}
+	assert.Len(t, counts, 4, "keys are in [1, 4]")
+	for k, c := range counts {
+		// expected n/4 = 1000, the standard deviation is about 27
+		assert.InDelta(t, n/4, c, 150, "key %s", k)
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math/big"
+)
+
+// baseWindow is the number of bits of the scalar handled by each window of the base point table.
+const baseWindow = 4
+
+/*
+baseTable returns the precomputed multiples of the base point used by ScalarBaseMult. The scalar
+is cut into windows of baseWindow bits, and for window i the table holds
+j*2^(4i)*G for j = 1, ..., 15, i.e. table[i][j-1] = j*2^(4i)*G.
+The entries are normalized to Z=1 so that ScalarBaseMult can use mixed additions.
+
+There is one window per 4 bits of N, the order of G, or of p+1 if N isn't set. The table is built
+the first time it's needed and then cached on the curve, sync.Once makes it safe to call
+ScalarBaseMult from several goroutines. Don't change G after the first call.
+For P-256 that's 64*15 points, roughly 100KB.
+*/
+func (curve *EllipticCurve) baseTable() [][]*jacobianPoint {
+	curve.baseOnce.Do(func() {
+		bits := curve.P.BitLen() + 1
+		if curve.N != nil {
+			bits = curve.N.BitLen()
+		}
+		windows := (bits + baseWindow - 1) / baseWindow
+		table := make([][]*jacobianPoint, windows)
+		base := curve.toJacobian(curve.G) // 2^(4i)*G
+		for i := range table {
+			table[i] = make([]*jacobianPoint, 1<<baseWindow-1)
+			table[i][0] = base
+			for j := 1; j < len(table[i]); j++ {
+				table[i][j] = curve.toJacobian(curve.toAffine(curve.jacobianAdd(table[i][j-1], base)))
+			}
+			// 16*2^(4i)*G = 2^(4(i+1))*G
+			base = curve.toJacobian(curve.toAffine(curve.jacobianAdd(table[i][len(table[i])-1], base)))
+		}
+		curve.baseTableCache = table
+	})
+	return curve.baseTableCache
+}
+
+/*
+ScalarBaseMult calculates nG where G is the base point of the curve, using the precomputed table
+from baseTable. Writing n = sum d_i 2^(4i) with 4-bit digits d_i, nG is the sum of the table
+entries d_i*2^(4i)*G, so it takes one addition per 4 bits of n and no doublings at all.
+For P-256 that's at most 64 additions instead of 256 doublings and about 128 additions for
+ScalarMult. The first call pays for building the table.
+
+n is reduced mod N first, if N is set. If n is longer than the table, for example because N isn't
+set, this falls back to ScalarMult.
+
+Note that this is vulnerable to timing analysis just like ScalarMult, the table lookup depends
+on the digits of n.
+*/
+func (curve *EllipticCurve) ScalarBaseMult(n []byte) *Point {
+	scalar := new(big.Int).SetBytes(n)
+	if curve.N != nil {
+		scalar.Mod(scalar, curve.N)
+	}
+	table := curve.baseTable()
+	if scalar.BitLen() > len(table)*baseWindow {
+		return curve.ScalarMult(n, curve.G)
+	}
+	ret := jacobianInfinity()
+	for i := range table {
+		if d := window(scalar, i*baseWindow, baseWindow); d != 0 {
+			ret = curve.jacobianAddMixed(ret, table[i][d-1])
+		}
+	}
+	return curve.toAffine(ret)
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalarBaseMult(t *testing.T) {
+	p256 := elliptic.P256()
+	curve := P256()
+	for i := 0; i < 10; i++ {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		x, y := p256.ScalarBaseMult(b)
+		assert.True(t, curve.Equal(curve.ScalarBaseMult(b), &Point{X: x, Y: y}))
+	}
+	assert.True(t, curve.ScalarBaseMult([]byte{0}).Infinity)
+	assert.True(t, curve.ScalarBaseMult(curve.N.Bytes()).Infinity)
+	assert.True(t, curve.Equal(curve.ScalarBaseMult([]byte{1}), curve.G))
+	// n >= N gets reduced
+	n := new(big.Int).Add(curve.N, big.NewInt(5))
+	assert.True(t, curve.Equal(curve.ScalarBaseMult(n.Bytes()), curve.ScalarMult([]byte{5}, curve.G)))
+}
+
+func TestScalarBaseMultSmall(t *testing.T) {
+	// (6,730) generates the whole group of 3566 points
+	curve := newSmallCurve(14, 19, 3623)
+	curve.G = pt(6, 730)
+	curve.N = big.NewInt(3566)
+	for n := int64(0); n < 4000; n += 7 {
+		want := curve.ScalarMult(big.NewInt(n).Bytes(), curve.G)
+		assert.True(t, curve.Equal(curve.ScalarBaseMult(big.NewInt(n).Bytes()), want), "n=%d", n)
+	}
+	// without N the table covers p+1 and longer scalars fall back to ScalarMult
+	curve = newSmallCurve(14, 19, 3623)
+	curve.G = pt(6, 730)
+	for _, n := range []int64{0, 1, 947, 3565, 3566, 3567, 1 << 20} {
+		want := curve.ScalarMult(big.NewInt(n).Bytes(), curve.G)
+		assert.True(t, curve.Equal(curve.ScalarBaseMult(big.NewInt(n).Bytes()), want), "n=%d", n)
+	}
+}
+
+// TestScalarBaseMultConcurrent builds the table from several goroutines at once, run with -race.
+func TestScalarBaseMultConcurrent(t *testing.T) {
+	curve := P256()
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	want := curve.ScalarMult(b, curve.G)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, curve.Equal(curve.ScalarBaseMult(b), want))
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	curve := P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	curve.ScalarBaseMult(n) // build the table outside of the timer
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curve.ScalarBaseMult(n)
+	}
+}
+
+func BenchmarkGoScalarBaseMult(b *testing.B) {
+	p256 := elliptic.P256()
+	n := make([]byte, 32)
+	_, _ = rand.Read(n)
+	for i := 0; i < b.N; i++ {
+		p256.ScalarBaseMult(n)
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// brainpoolPairs are the rP-curves with their isomorphic tP-curves.
+var brainpoolPairs = [][2]func() *EllipticCurve{
+	{BrainpoolP160r1, BrainpoolP160t1},
+	{BrainpoolP192r1, BrainpoolP192t1},
+	{BrainpoolP224r1, BrainpoolP224t1},
+	{BrainpoolP256r1, BrainpoolP256t1},
+	{BrainpoolP320r1, BrainpoolP320t1},
+	{BrainpoolP384r1, BrainpoolP384t1},
+	{BrainpoolP512r1, BrainpoolP512t1},
+}
+
+func TestBrainpoolParams(t *testing.T) {
+	for _, pair := range brainpoolPairs {
+		for _, curve := range []*EllipticCurve{pair[0](), pair[1]()} {
+			assert.Equal(t, curve.BitSize, curve.P.BitLen(), curve.Name)
+			assert.True(t, curve.P.ProbablyPrime(20), curve.Name)
+			assert.True(t, curve.N.ProbablyPrime(20), curve.Name)
+			assert.True(t, curve.IsOnCurve(curve.G), curve.Name)
+			assert.True(t, curve.ScalarMult(curve.N.Bytes(), curve.G).Infinity, curve.Name)
+		}
+		assert.Equal(t, aMinus3, pair[1]().aShape(), pair[1]().Name)
+	}
+}
+
+// brainpoolZ returns Z = (y_t/y_r)/(x_t/x_r) from the base points, since G_t = (x_r*Z^2, y_r*Z^3).
+func brainpoolZ(r, tw *EllipticCurve) *big.Int {
+	P := r.P
+	z2 := new(big.Int).ModInverse(r.G.X, P)
+	z2.Mul(z2, tw.G.X)
+	z3 := new(big.Int).ModInverse(r.G.Y, P)
+	z3.Mul(z3, tw.G.Y)
+	z := z2.ModInverse(z2, P)
+	z.Mul(z, z3)
+	return z.Mod(z, P)
+}
+
+// isomorphism maps p on the rP-curve to (xZ^2, yZ^3) on the tP-curve.
+func isomorphism(P, z *big.Int, p *Point) *Point {
+	z2 := new(big.Int).Exp(z, big.NewInt(2), P)
+	z3 := new(big.Int).Exp(z, big.NewInt(3), P)
+	x := new(big.Int).Mul(p.X, z2)
+	y := new(big.Int).Mul(p.Y, z3)
+	return &Point{X: x.Mod(x, P), Y: y.Mod(y, P)}
+}
+
+// TestBrainpoolIsomorphism checks RFC 5639's isomorphism between each rP-curve and its tP-curve.
+func TestBrainpoolIsomorphism(t *testing.T) {
+	for _, pair := range brainpoolPairs {
+		r, tw := pair[0](), pair[1]()
+		assert.Equal(t, 0, r.P.Cmp(tw.P), tw.Name)
+		assert.Equal(t, 0, r.N.Cmp(tw.N), tw.Name)
+		P := r.P
+		z := brainpoolZ(r, tw)
+		// G_r maps to G_t, and the curve constants are A_t = A*Z^4, B_t = B*Z^6
+		assert.True(t, tw.Equal(isomorphism(P, z, r.G), tw.G), tw.Name)
+		at := new(big.Int).Exp(z, big.NewInt(4), P)
+		at.Mul(at, r.A)
+		assert.Equal(t, 0, at.Mod(at, P).Cmp(new(big.Int).Mod(tw.A, P)), tw.Name)
+		bt := new(big.Int).Exp(z, big.NewInt(6), P)
+		bt.Mul(bt, r.B)
+		assert.Equal(t, 0, bt.Mod(bt, P).Cmp(tw.B), tw.Name)
+		// the map is a group isomorphism, so it commutes with scalar multiplication
+		k, _ := rand.Int(rand.Reader, r.N)
+		assert.True(t, tw.Equal(isomorphism(P, z, r.ScalarMult(k.Bytes(), r.G)), tw.ScalarMult(k.Bytes(), tw.G)), tw.Name)
+	}
+}
+
+// TestBrainpoolZ compares Z with the values published in RFC 5639.
+func TestBrainpoolZ(t *testing.T) {
+	for _, c := range []struct {
+		r, tw func() *EllipticCurve
+		z     string
+	}{
+		{BrainpoolP160r1, BrainpoolP160t1, "24dbff5dec9b986bbfe5295a29bfbae45e0f5d0b"},
+		{BrainpoolP256r1, BrainpoolP256t1, "3e2d4bd9597b58639ae7aa669cab9837cf5cf20a2c852d10f655668dfc150ef0"},
+	} {
+		z, _ := new(big.Int).SetString(c.z, 16)
+		assert.Equal(t, z.Text(16), brainpoolZ(c.r(), c.tw()).Text(16))
+	}
+}
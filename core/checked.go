@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+)
+
+/*
+The operations in arithmetic.go trust their inputs: a point that's not on the curve still gives an
+answer, but the answer lives on another curve y^2 = x^3+Ax+B' (B doesn't appear in the formulas),
+maybe one with a small subgroup. An attacker who sends such points and sees k*P learns k mod the small
+orders, one after the other, that's the invalid-curve attack. Small subgroup attacks are the same
+idea with points of small order that are on the curve, when the cofactor isn't 1.
+
+The functions here check points from untrusted sources first and return an error instead.
+The unchecked methods stay for internal use and for points we computed ourselves.
+*/
+
+/*
+ValidatePoint returns an error unless p is in the subgroup generated by G: the coordinates are in
+[0, p), p is on the curve and N*p is the point at infinity. If the cofactor H is 1 every point on the
+curve is in the subgroup, so the last check, which costs a scalar multiplication, is skipped.
+The point at infinity is in the subgroup, see ValidatePublicKey to reject it.
+*/
+func (curve *EllipticCurve) ValidatePoint(p *Point) error {
+	if p == nil {
+		return errors.New("core: point is nil")
+	}
+	if p.Infinity {
+		return nil
+	}
+	if p.X == nil || p.Y == nil {
+		return errors.New("core: point has nil coordinates")
+	}
+	if p.X.Sign() < 0 || p.X.Cmp(curve.P) >= 0 || p.Y.Sign() < 0 || p.Y.Cmp(curve.P) >= 0 {
+		return errors.New("core: coordinate is out of range")
+	}
+	if !curve.IsOnCurve(p) {
+		return errors.New("core: point is not on the curve")
+	}
+	if curve.H != nil && curve.H.Cmp(big.NewInt(1)) == 0 {
+		return nil
+	}
+	if curve.N == nil {
+		return errors.New("core: N is required to check the subgroup")
+	}
+	if !curve.ScalarMult(curve.N.Bytes(), p).Infinity {
+		return errors.New("core: point is not in the subgroup generated by G")
+	}
+	return nil
+}
+
+/*
+ValidatePublicKey is ValidatePoint but also rejects the point at infinity, which is never a valid
+public key. This is the public key validation of SEC1 3.2.2.1.
+*/
+func (curve *EllipticCurve) ValidatePublicKey(p *Point) error {
+	if p != nil && p.Infinity {
+		return errors.New("core: public key is the point at infinity")
+	}
+	return curve.ValidatePoint(p)
+}
+
+// SafeAdd returns p1+p2 after checking both points with ValidatePoint.
+func (curve *EllipticCurve) SafeAdd(p1, p2 *Point) (*Point, error) {
+	if err := curve.ValidatePoint(p1); err != nil {
+		return nil, err
+	}
+	if err := curve.ValidatePoint(p2); err != nil {
+		return nil, err
+	}
+	return curve.Add(p1, p2), nil
+}
+
+/*
+SafeScalarMult returns n*p after checking p with ValidatePoint. It uses the Montgomery ladder,
+since a point from outside is usually multiplied by a secret, e.g. in DH.
+*/
+func (curve *EllipticCurve) SafeScalarMult(n []byte, p *Point) (*Point, error) {
+	if err := curve.ValidatePoint(p); err != nil {
+		return nil, err
+	}
+	return curve.ScalarMultLadder(n, p), nil
+}
+
+// SafeDoubleScalarMult returns a*p+b*q after checking p and q with ValidatePoint, a and b are public.
+func (curve *EllipticCurve) SafeDoubleScalarMult(a []byte, p *Point, b []byte, q *Point) (*Point, error) {
+	if err := curve.ValidatePoint(p); err != nil {
+		return nil, err
+	}
+	if err := curve.ValidatePoint(q); err != nil {
+		return nil, err
+	}
+	return curve.DoubleScalarMult(a, p, b, q), nil
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePoint(t *testing.T) {
+	curve := P256()
+	k, _ := rand.Int(rand.Reader, curve.N)
+	p := curve.ScalarBaseMult(k.Bytes())
+	assert.NoError(t, curve.ValidatePoint(p))
+	assert.NoError(t, curve.ValidatePublicKey(p))
+	assert.NoError(t, curve.ValidatePoint(Infinity()))
+	assert.Error(t, curve.ValidatePublicKey(Infinity()))
+	assert.Error(t, curve.ValidatePoint(nil))
+	assert.Error(t, curve.ValidatePoint(&Point{X: p.X}))
+	assert.Error(t, curve.ValidatePoint(&Point{X: p.X, Y: new(big.Int).Add(p.Y, big.NewInt(1))}))
+	assert.Error(t, curve.ValidatePoint(&Point{X: p.X, Y: new(big.Int).Add(p.Y, curve.P)}))
+	assert.Error(t, curve.ValidatePoint(&Point{X: new(big.Int).Neg(p.X), Y: p.Y}))
+}
+
+// TestValidatePointSubgroup uses the toy curve with 3566 points and the subgroup of order 1783.
+func TestValidatePointSubgroup(t *testing.T) {
+	for _, h := range []*big.Int{big.NewInt(2), nil} {
+		curve := newSmallCurve(14, 19, 3623)
+		curve.G, curve.N, curve.H = pt(2521, 3601), big.NewInt(1783), h
+		assert.NoError(t, curve.ValidatePoint(curve.G))
+		assert.NoError(t, curve.ValidatePoint(curve.ScalarMult([]byte{100}, curve.G)))
+		// (6,730) generates the whole group and (802,0) has order 2, neither is in the subgroup
+		assert.Error(t, curve.ValidatePoint(pt(6, 730)))
+		assert.Error(t, curve.ValidatePoint(pt(802, 0)))
+		_, err := curve.SafeScalarMult([]byte{5}, pt(802, 0))
+		assert.Error(t, err)
+	}
+	// without N there's no way to check
+	curve := newSmallCurve(14, 19, 3623)
+	assert.Error(t, curve.ValidatePoint(pt(6, 730)))
+}
+
+// TestSafeScalarMultInvalidCurve shows what the checks prevent: ScalarMult happily multiplies a
+// point of another curve y^2 = x^3-3x+B', SafeScalarMult refuses.
+func TestSafeScalarMultInvalidCurve(t *testing.T) {
+	curve := P256()
+	// (1,1) is on y^2 = x^3-3x+3 and not on P-256
+	bad := pt(1, 1)
+	assert.False(t, curve.IsOnCurve(bad))
+	assert.NotNil(t, curve.ScalarMult([]byte{7}, bad))
+	_, err := curve.SafeScalarMult([]byte{7}, bad)
+	assert.Error(t, err)
+	_, err = curve.SafeAdd(curve.G, bad)
+	assert.Error(t, err)
+	_, err = curve.SafeDoubleScalarMult([]byte{1}, curve.G, []byte{7}, bad)
+	assert.Error(t, err)
+}
+
+func TestSafeOperations(t *testing.T) {
+	curve := P256()
+	a, _ := rand.Int(rand.Reader, curve.N)
+	b, _ := rand.Int(rand.Reader, curve.N)
+	p := curve.ScalarBaseMult(a.Bytes())
+	q := curve.ScalarBaseMult(b.Bytes())
+
+	sum, err := curve.SafeAdd(p, q)
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(sum, curve.Add(p, q)))
+	prod, err := curve.SafeScalarMult(b.Bytes(), p)
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(prod, curve.ScalarMult(b.Bytes(), p)))
+	dbl, err := curve.SafeDoubleScalarMult(a.Bytes(), p, b.Bytes(), q)
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(dbl, curve.DoubleScalarMult(a.Bytes(), p, b.Bytes(), q)))
+}
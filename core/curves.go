@@ -0,0 +1,27 @@
+package core
+
+import (
+	"math/big"
+)
+
+/*
+curveFromHex returns the curve y^2 = x^3+ax+b mod p with base point (gx, gy) of order n, all
+given in hex like in the standards, e.g. FIPS 186-4 and SEC2, and openssl ecparam -text.
+All the standard curves here have cofactor 1.
+The constants are known to be valid, so parse errors aren't checked, and setup() runs right away.
+*/
+func curveFromHex(name string, bitSize int, p, a, b, n, gx, gy string) *EllipticCurve {
+	curve := &EllipticCurve{Name: name}
+	curve.P, _ = new(big.Int).SetString(p, 16)
+	curve.A, _ = new(big.Int).SetString(a, 16)
+	curve.B, _ = new(big.Int).SetString(b, 16)
+	curve.N, _ = new(big.Int).SetString(n, 16)
+	curve.H = big.NewInt(1)
+	g := &Point{}
+	g.X, _ = new(big.Int).SetString(gx, 16)
+	g.Y, _ = new(big.Int).SetString(gy, 16)
+	curve.G = g
+	curve.BitSize = bitSize
+	curve.setup()
+	return curve
+}
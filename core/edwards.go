@@ -0,0 +1,288 @@
+package core
+
+import (
+	"ecc/field"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+/*
+EdwardsCurve represents a twisted Edwards curve ax^2+y^2 = 1+dx^2y^2, x,y in F_p, like Ed25519.
+The identity is the ordinary point (0,1), the Infinity flag of Point isn't used, and -(x,y) = (-x,y).
+
+The addition law (x1,y1)+(x2,y2) = ((x1y2+y1x2)/(1+dx1x2y1y2), (y1y2-ax1x2)/(1-dx1x2y1y2))
+is the same for adding and doubling, and it's complete when a is a square and d is not, i.e. the
+denominators are never 0, so there are no special cases like for Weierstrass curves.
+Bernstein et al., "Twisted Edwards Curves", https://eprint.iacr.org/2008/013
+
+The arithmetic here is affine on big.Int, i.e. two ModInverse per addition, since this is
+only meant to show that the protocols work on other curve shapes through Group.
+*/
+type EdwardsCurve struct {
+	P       *big.Int // order of the underlying field
+	N       *big.Int // order of the base point
+	A, D    *big.Int // constants of the curve equation
+	G       *Point   // base point
+	BitSize int      // size of the underlying field in bits
+	Name    string   // name of the curve
+
+	setupOnce sync.Once    // guards fp
+	fp        *field.Field // F_p, for square roots in UnmarshalPoint
+}
+
+/*
+Ed25519 returns the twisted Edwards curve -x^2+y^2 = 1-(121665/121666)x^2y^2 over p = 2^255-19 from
+RFC 8032 5.1, which is birationally equivalent to Curve25519. N is the order of the base point, the
+whole group has 8N points.
+*/
+func Ed25519() *EdwardsCurve {
+	curve := &EdwardsCurve{Name: "Ed25519"}
+	curve.P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	curve.A = big.NewInt(-1)
+	curve.D, _ = new(big.Int).SetString("37095705934669439343138083508754565189542113879843219016388785533085940283555", 10)
+	curve.N, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	p := &Point{}
+	p.X, _ = new(big.Int).SetString("15112221349535400772501151409588531511454012693041857206046113283949847762202", 10)
+	p.Y, _ = new(big.Int).SetString("46316835694926478169428394003475163141307993866256225615783033603165251855960", 10)
+	curve.G = p
+	curve.BitSize = 255
+	return curve
+}
+
+// Identity returns (0,1), the identity of the group.
+func (curve *EdwardsCurve) Identity() *Point {
+	return &Point{X: new(big.Int), Y: big.NewInt(1)}
+}
+
+// Generator returns a copy of the base point G.
+func (curve *EdwardsCurve) Generator() *Point {
+	return copyPoint(curve.G)
+}
+
+// Order returns a copy of N, the order of G.
+func (curve *EdwardsCurve) Order() *big.Int {
+	return new(big.Int).Set(curve.N)
+}
+
+// Equal returns true if the two points are equal.
+func (curve *EdwardsCurve) Equal(p1, p2 *Point) bool {
+	return p1.X.Cmp(p2.X) == 0 && p1.Y.Cmp(p2.Y) == 0
+}
+
+// IsOnCurve returns true if ax^2+y^2 = 1+dx^2y^2 mod p.
+func (curve *EdwardsCurve) IsOnCurve(p *Point) bool {
+	x2 := new(big.Int).Mul(p.X, p.X)
+	y2 := new(big.Int).Mul(p.Y, p.Y)
+	lhs := new(big.Int).Mul(curve.A, x2)
+	lhs.Add(lhs, y2)
+	lhs.Mod(lhs, curve.P)
+	rhs := new(big.Int).Mul(curve.D, x2)
+	rhs.Mul(rhs, y2)
+	rhs.Add(rhs, big.NewInt(1))
+	rhs.Mod(rhs, curve.P)
+	return lhs.Cmp(rhs) == 0
+}
+
+// Add returns p1+p2 with the complete addition law, which also doubles.
+func (curve *EdwardsCurve) Add(p1, p2 *Point) *Point {
+	P := curve.P
+	x1x2 := new(big.Int).Mul(p1.X, p2.X)
+	y1y2 := new(big.Int).Mul(p1.Y, p2.Y)
+	t := new(big.Int).Mul(x1x2, y1y2)
+	t.Mul(t, curve.D)
+	t.Mod(t, P) // dx1x2y1y2
+
+	x := new(big.Int).Mul(p1.X, p2.Y)
+	x.Add(x, new(big.Int).Mul(p1.Y, p2.X))
+	den := new(big.Int).Add(big.NewInt(1), t)
+	den.ModInverse(den, P)
+	x.Mul(x, den)
+	x.Mod(x, P)
+
+	y := new(big.Int).Mul(curve.A, x1x2)
+	y.Sub(y1y2, y)
+	den.Sub(big.NewInt(1), t)
+	den.Mod(den, P)
+	den.ModInverse(den, P)
+	y.Mul(y, den)
+	y.Mod(y, P)
+	return &Point{X: x, Y: y}
+}
+
+// Negate returns -p = (-x,y).
+func (curve *EdwardsCurve) Negate(p *Point) *Point {
+	x := new(big.Int).Neg(p.X)
+	x.Mod(x, curve.P)
+	return &Point{X: x, Y: new(big.Int).Set(p.Y)}
+}
+
+// ScalarMult returns np by double and add, which is vulnerable to timing attack.
+func (curve *EdwardsCurve) ScalarMult(n []byte, p *Point) *Point {
+	k := new(big.Int).SetBytes(n)
+	ret := curve.Identity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		ret = curve.Add(ret, ret)
+		if k.Bit(i) == 1 {
+			ret = curve.Add(ret, p)
+		}
+	}
+	return ret
+}
+
+// ScalarBaseMult returns nG.
+func (curve *EdwardsCurve) ScalarBaseMult(n []byte) *Point {
+	return curve.ScalarMult(n, curve.G)
+}
+
+/*
+ScalarMultSecret returns np for a secret n with the Montgomery ladder, see
+EllipticCurve.ScalarMultLadder. The addition law is complete, so there's no special case for the
+identity and every bit costs one addition and one doubling. n is padded to the bit length of N,
+or to len(n)*8 if it's longer.
+NOTE: the affine arithmetic is big.Int with a ModInverse per addition, which isn't constant time.
+*/
+func (curve *EdwardsCurve) ScalarMultSecret(n []byte, p *Point) *Point {
+	k := new(big.Int).SetBytes(n)
+	bits := len(n) * 8
+	if curve.N.BitLen() > bits {
+		bits = curve.N.BitLen()
+	}
+	r0, r1 := curve.Identity(), copyPoint(p)
+	for i := bits - 1; i >= 0; i-- {
+		b := k.Bit(i)
+		pair := [2]*Point{r0, r1}
+		r0, r1 = pair[b], pair[b^1]
+		r1 = curve.Add(r0, r1)
+		r0 = curve.Add(r0, r0)
+		pair = [2]*Point{r0, r1}
+		r0, r1 = pair[b], pair[b^1]
+	}
+	return r0
+}
+
+// DoubleScalarMult returns ap+bq with Shamir's trick, see EllipticCurve.DoubleScalarMult.
+func (curve *EdwardsCurve) DoubleScalarMult(a []byte, p *Point, b []byte, q *Point) *Point {
+	sa := new(big.Int).SetBytes(a)
+	sb := new(big.Int).SetBytes(b)
+	table := [4]*Point{nil, p, q, curve.Add(p, q)}
+	bits := sa.BitLen()
+	if sb.BitLen() > bits {
+		bits = sb.BitLen()
+	}
+	ret := curve.Identity()
+	for i := bits - 1; i >= 0; i-- {
+		ret = curve.Add(ret, ret)
+		if j := sa.Bit(i) + 2*sb.Bit(i); j != 0 {
+			ret = curve.Add(ret, table[j])
+		}
+	}
+	return ret
+}
+
+// byteLen returns the length of an encoded point, enough for y and the sign bit of x.
+func (curve *EdwardsCurve) byteLen() int {
+	return (curve.P.BitLen() + 8) / 8
+}
+
+/*
+MarshalPoint returns the encoding of p from RFC 8032 5.1.2: y in little-endian, with the lowest
+bit of x in the most significant bit of the last byte. That's 32 bytes for Ed25519.
+*/
+func (curve *EdwardsCurve) MarshalPoint(p *Point) []byte {
+	l := curve.byteLen()
+	ret := make([]byte, l)
+	p.Y.FillBytes(ret)
+	for i := 0; i < l/2; i++ {
+		ret[i], ret[l-1-i] = ret[l-1-i], ret[i]
+	}
+	ret[l-1] |= byte(p.X.Bit(0)) << 7
+	return ret
+}
+
+/*
+ValidatePoint returns an error unless p is in the subgroup generated by G: the coordinates are in
+[0, p), p is on the curve and N*p is the identity. The whole group of Ed25519 has 8N points, so
+unlike on the NIST curves, being on the curve is not enough. The identity is in the subgroup.
+*/
+func (curve *EdwardsCurve) ValidatePoint(p *Point) error {
+	if p == nil || p.X == nil || p.Y == nil {
+		return errors.New("core: point is nil")
+	}
+	if p.X.Sign() < 0 || p.X.Cmp(curve.P) >= 0 || p.Y.Sign() < 0 || p.Y.Cmp(curve.P) >= 0 {
+		return errors.New("core: coordinate is out of range")
+	}
+	if !curve.IsOnCurve(p) {
+		return errors.New("core: point is not on the curve")
+	}
+	if !curve.Equal(curve.ScalarMult(curve.N.Bytes(), p), curve.Identity()) {
+		return errors.New("core: point is not in the subgroup generated by G")
+	}
+	return nil
+}
+
+/*
+UnmarshalPoint decodes a point encoded by MarshalPoint, RFC 8032 5.1.3. x is recovered from
+x^2 = (1-y^2)/(a-dy^2) with a square root and picked by the sign bit. It returns an error if data
+has the wrong length, y isn't in [0, p), or there's no such point on the curve.
+It also rejects points outside the subgroup generated by G, e.g. the small order points like
+(0,-1), see ValidatePoint. RFC 8032 doesn't, but then a peer could hand us a point of order 8.
+*/
+func (curve *EdwardsCurve) UnmarshalPoint(data []byte) (*Point, error) {
+	l := curve.byteLen()
+	if len(data) != l {
+		return nil, errors.New("core: invalid Edwards point encoding")
+	}
+	b := make([]byte, l)
+	for i := range data {
+		b[l-1-i] = data[i]
+	}
+	sign := uint(b[0] >> 7)
+	b[0] &= 0x7f
+	y := new(big.Int).SetBytes(b)
+	if y.Cmp(curve.P) >= 0 {
+		return nil, errors.New("core: coordinate is out of range")
+	}
+
+	fp := curve.primeField()
+	var one, ey, u, v, t, x field.Element
+	fp.SetOne(&one)
+	fp.SetBigInt(&ey, y)
+	fp.Square(&t, &ey)
+	fp.Sub(&u, &one, &t) // 1-y^2
+	fp.SetBigInt(&v, curve.D)
+	fp.Mul(&v, &v, &t)
+	fp.SetBigInt(&t, curve.A)
+	fp.Sub(&v, &t, &v) // a-dy^2
+	if fp.IsZero(&v) {
+		return nil, errors.New("core: point is not on the curve")
+	}
+	fp.Inverse(&v, &v)
+	fp.Mul(&u, &u, &v)
+	if fp.Sqrt(&x, &u) == nil {
+		return nil, errors.New("core: point is not on the curve")
+	}
+	p := &Point{X: fp.BigInt(&x), Y: y}
+	if len(p.X.Bits()) == 0 && sign == 1 {
+		return nil, errors.New("core: invalid Edwards point encoding")
+	}
+	if p.X.Bit(0) != sign {
+		p.X.Sub(curve.P, p.X)
+	}
+	if err := curve.ValidatePoint(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// primeField returns F_p, it's set up on first use.
+func (curve *EdwardsCurve) primeField() *field.Field {
+	curve.setupOnce.Do(func() {
+		fp, err := field.NewField(curve.P)
+		if err != nil {
+			panic("core: " + err.Error())
+		}
+		curve.fp = fp
+	})
+	return curve.fp
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEd25519Params(t *testing.T) {
+	curve := Ed25519()
+	assert.True(t, curve.IsOnCurve(curve.G))
+	assert.True(t, curve.Equal(curve.ScalarBaseMult(curve.N.Bytes()), curve.Identity()))
+	// RFC 8032 5.1: the base point encodes as 0x58 followed by 31 times 0x66
+	assert.Equal(t, "5866666666666666666666666666666666666666666666666666666666666666", hex.EncodeToString(curve.MarshalPoint(curve.G)))
+}
+
+// ed25519Public derives the public key from a secret key like RFC 8032 5.1.5, to check our arithmetic.
+func ed25519Public(curve *EdwardsCurve, secret []byte) []byte {
+	h := sha512.Sum512(secret)
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	// the scalar is little-endian, ours are big-endian
+	s := make([]byte, 32)
+	for i := range s {
+		s[i] = h[31-i]
+	}
+	return curve.MarshalPoint(curve.ScalarBaseMult(s))
+}
+
+func TestEd25519PublicKey(t *testing.T) {
+	curve := Ed25519()
+	// RFC 8032 7.1 TEST 1
+	secret, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	assert.Equal(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a", hex.EncodeToString(ed25519Public(curve, secret)))
+	// and against crypto/ed25519
+	seed := make([]byte, ed25519.SeedSize)
+	for i := 0; i < 5; i++ {
+		seed[0] = byte(i)
+		want := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+		assert.Equal(t, []byte(want), ed25519Public(curve, seed))
+	}
+}
+
+func TestEdwardsUnmarshal(t *testing.T) {
+	curve := Ed25519()
+	for i := int64(0); i < 10; i++ {
+		p := curve.ScalarBaseMult(big.NewInt(1000*i + 7).Bytes())
+		q, err := curve.UnmarshalPoint(curve.MarshalPoint(p))
+		assert.NoError(t, err)
+		assert.True(t, curve.Equal(p, q))
+		q, err = curve.UnmarshalPoint(curve.MarshalPoint(curve.Negate(p)))
+		assert.NoError(t, err)
+		assert.True(t, curve.Equal(curve.Negate(p), q))
+	}
+	q, err := curve.UnmarshalPoint(curve.MarshalPoint(curve.Identity()))
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(curve.Identity(), q))
+
+	_, err = curve.UnmarshalPoint(make([]byte, 31))
+	assert.Error(t, err)
+	// y = p
+	b := curve.P.FillBytes(make([]byte, 32))
+	for i := 0; i < 16; i++ {
+		b[i], b[31-i] = b[31-i], b[i]
+	}
+	_, err = curve.UnmarshalPoint(b)
+	assert.Error(t, err)
+	// y = 1 gives x = 0, which can't have the sign bit set
+	b = make([]byte, 32)
+	b[0], b[31] = 1, 0x80
+	_, err = curve.UnmarshalPoint(b)
+	assert.Error(t, err)
+	// y = 2 is not on the curve: (1-4)/(-1-4d) is not a square
+	b[0], b[31] = 2, 0
+	_, err = curve.UnmarshalPoint(b)
+	assert.Error(t, err)
+}
+
+// TestEdwardsSmallOrder checks that points outside the subgroup of order N are rejected.
+func TestEdwardsSmallOrder(t *testing.T) {
+	curve := Ed25519()
+	// (0,-1) is on the curve and has order 2
+	t2 := &Point{X: big.NewInt(0), Y: new(big.Int).Sub(curve.P, big.NewInt(1))}
+	assert.True(t, curve.IsOnCurve(t2))
+	assert.True(t, curve.Equal(curve.Add(t2, t2), curve.Identity()))
+	_, err := curve.UnmarshalPoint(curve.MarshalPoint(t2))
+	assert.Error(t, err)
+	assert.Error(t, curve.ValidatePoint(t2))
+
+	// G+(0,-1) is on the curve and has order 2N
+	p := curve.Add(curve.G, t2)
+	assert.True(t, curve.IsOnCurve(p))
+	_, err = curve.UnmarshalPoint(curve.MarshalPoint(p))
+	assert.Error(t, err)
+
+	assert.NoError(t, curve.ValidatePoint(curve.G))
+	assert.NoError(t, curve.ValidatePoint(curve.Identity()))
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+)
+
+/*
+Points are encoded as in SEC1 (https://www.secg.org/sec1-v2.pdf) 2.3.3 and 2.3.4, which is also
+what crypto/elliptic's Marshal and MarshalCompressed do:
+
+- the point at infinity is the single byte 0x00,
+- uncompressed points are 0x04 || X || Y,
+- compressed points are 0x02 || X if Y is even and 0x03 || X if Y is odd,
+
+where X and Y are big-endian and padded to the byte length of p. crypto/elliptic has no encoding
+for the point at infinity, its Unmarshal rejects 0x00.
+*/
+
+// byteLen returns the length of an encoded coordinate, the byte length of p.
+func (curve *EllipticCurve) byteLen() int {
+	return (curve.P.BitLen() + 7) / 8
+}
+
+// MarshalPoint returns the uncompressed SEC1 encoding of p.
+func (curve *EllipticCurve) MarshalPoint(p *Point) []byte {
+	if p.Infinity {
+		return []byte{0x00}
+	}
+	l := curve.byteLen()
+	ret := make([]byte, 1+2*l)
+	ret[0] = 0x04
+	p.X.FillBytes(ret[1 : 1+l])
+	p.Y.FillBytes(ret[1+l:])
+	return ret
+}
+
+// MarshalCompressed returns the compressed SEC1 encoding of p.
+func (curve *EllipticCurve) MarshalCompressed(p *Point) []byte {
+	if p.Infinity {
+		return []byte{0x00}
+	}
+	ret := make([]byte, 1+curve.byteLen())
+	ret[0] = 0x02 | byte(p.Y.Bit(0))
+	p.X.FillBytes(ret[1:])
+	return ret
+}
+
+/*
+UnmarshalPoint decodes an uncompressed point or the point at infinity, as returned by MarshalPoint
+or elliptic.Marshal. It returns an error if data has the wrong length or prefix, if a coordinate
+isn't in [0, p), or if the point is not on the curve.
+*/
+func (curve *EllipticCurve) UnmarshalPoint(data []byte) (*Point, error) {
+	if len(data) == 1 && data[0] == 0x00 {
+		return Infinity(), nil
+	}
+	l := curve.byteLen()
+	if len(data) != 1+2*l || data[0] != 0x04 {
+		return nil, errors.New("core: invalid uncompressed point encoding")
+	}
+	p := &Point{X: new(big.Int).SetBytes(data[1 : 1+l]), Y: new(big.Int).SetBytes(data[1+l:])}
+	if p.X.Cmp(curve.P) >= 0 || p.Y.Cmp(curve.P) >= 0 {
+		return nil, errors.New("core: coordinate is out of range")
+	}
+	if !curve.IsOnCurve(p) {
+		return nil, errors.New("core: point is not on the curve")
+	}
+	return p, nil
+}
+
+/*
+UnmarshalCompressed decodes a compressed point or the point at infinity, as returned by
+MarshalCompressed or elliptic.MarshalCompressed. Y is recovered with PointFromX, so this returns
+an error if X isn't in [0, p) or no point on the curve has this X.
+*/
+func (curve *EllipticCurve) UnmarshalCompressed(data []byte) (*Point, error) {
+	if len(data) == 1 && data[0] == 0x00 {
+		return Infinity(), nil
+	}
+	if len(data) != 1+curve.byteLen() || (data[0] != 0x02 && data[0] != 0x03) {
+		return nil, errors.New("core: invalid compressed point encoding")
+	}
+	return curve.PointFromX(new(big.Int).SetBytes(data[1:]), data[0] == 0x03)
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMarshalInterop checks both encodings against crypto/elliptic in both directions.
+func TestMarshalInterop(t *testing.T) {
+	p256 := elliptic.P256()
+	curve := P256()
+	for i := 0; i < 20; i++ {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		x, y := p256.ScalarBaseMult(b)
+		p := &Point{X: x, Y: y}
+
+		assert.Equal(t, elliptic.Marshal(p256, x, y), curve.MarshalPoint(p))
+		assert.Equal(t, elliptic.MarshalCompressed(p256, x, y), curve.MarshalCompressed(p))
+
+		q, err := curve.UnmarshalPoint(elliptic.Marshal(p256, x, y))
+		assert.NoError(t, err)
+		assert.True(t, curve.Equal(p, q))
+		q, err = curve.UnmarshalCompressed(elliptic.MarshalCompressed(p256, x, y))
+		assert.NoError(t, err)
+		assert.True(t, curve.Equal(p, q))
+
+		ux, uy := elliptic.Unmarshal(p256, curve.MarshalPoint(p))
+		assert.True(t, curve.Equal(p, &Point{X: ux, Y: uy}))
+		ux, uy = elliptic.UnmarshalCompressed(p256, curve.MarshalCompressed(p))
+		assert.True(t, curve.Equal(p, &Point{X: ux, Y: uy}))
+	}
+}
+
+func TestMarshalSmallCurves(t *testing.T) {
+	for _, curve := range smallCurves {
+		if curve.P.Int64() >= 100 {
+			continue
+		}
+		for _, p := range smallCurvePoints(curve) {
+			q, err := curve.UnmarshalPoint(curve.MarshalPoint(p))
+			assert.NoError(t, err, "%s %v", curve.Name, p)
+			assert.True(t, curve.Equal(p, q), "%s %v", curve.Name, p)
+			q, err = curve.UnmarshalCompressed(curve.MarshalCompressed(p))
+			assert.NoError(t, err, "%s %v", curve.Name, p)
+			assert.True(t, curve.Equal(p, q), "%s %v", curve.Name, p)
+		}
+	}
+}
+
+func TestMarshalInfinity(t *testing.T) {
+	curve := P256()
+	assert.Equal(t, []byte{0x00}, curve.MarshalPoint(Infinity()))
+	assert.Equal(t, []byte{0x00}, curve.MarshalCompressed(Infinity()))
+	p, err := curve.UnmarshalPoint([]byte{0x00})
+	assert.NoError(t, err)
+	assert.True(t, p.Infinity)
+	p, err = curve.UnmarshalCompressed([]byte{0x00})
+	assert.NoError(t, err)
+	assert.True(t, p.Infinity)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	curve := smallCurves[1] // p = 3623, 2 bytes per coordinate
+	encode := func(prefix byte, coords ...int64) []byte {
+		ret := []byte{prefix}
+		for _, c := range coords {
+			ret = append(ret, big.NewInt(c).FillBytes(make([]byte, 2))...)
+		}
+		return ret
+	}
+	for name, data := range map[string][]byte{
+		"empty":         {},
+		"wrong prefix":  encode(0x05, 6, 730),
+		"compressed":    encode(0x02, 6),
+		"too short":     encode(0x04, 6, 730)[:4],
+		"too long":      append(encode(0x04, 6, 730), 0),
+		"long infinity": {0x00, 0x00},
+		"not on curve":  encode(0x04, 6, 731),
+		"x = p":         encode(0x04, 3623+6, 730),
+		"y = p":         encode(0x04, 802, 3623),
+	} {
+		_, err := curve.UnmarshalPoint(data)
+		assert.Error(t, err, name)
+	}
+	for name, data := range map[string][]byte{
+		"empty":         {},
+		"wrong prefix":  encode(0x04, 6),
+		"uncompressed":  encode(0x04, 6, 730),
+		"too short":     encode(0x02, 6)[:2],
+		"not on curve":  encode(0x02, 3), // 88 is not a square mod 3623
+		"x = p":         encode(0x02, 3623+6),
+		"odd 2-torsion": encode(0x03, 802),
+	} {
+		_, err := curve.UnmarshalCompressed(data)
+		assert.Error(t, err, name)
+	}
+}
@@ -23,13 +23,14 @@ func GoECCExample() {
 	fmt.Println("order of base point N is prime?", curveParams.N.ProbablyPrime(20))
 
 	// Generate key pair
-	keyPair, err := GenerateKey(curve)
+	priv, x, y, err := elliptic.GenerateKey(curve, rand.Reader)
 	if err != nil {
 		fmt.Println(err)
+		return
 	}
 	fmt.Println("------ key pair ------")
-	fmt.Println("private key", keyPair.Priv)
-	fmt.Println("public key", keyPair.Pub.X, keyPair.Pub.Y)
+	fmt.Println("private key", priv)
+	fmt.Println("public key", x, y)
 
 	// Generate random point on the curve by n*P
 	b1 := make([]byte, 32)
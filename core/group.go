@@ -0,0 +1,73 @@
+package core
+
+import "math/big"
+
+/*
+Group is what the protocols in ds, misc and dh need from a curve: the group law, scalar
+multiplications, the order of the generator and an encoding of points. The same protocol code then
+runs on any curve that implements it, EllipticCurve for short Weierstrass curves and EdwardsCurve
+for twisted Edwards curves.
+
+Scalars are big-endian []byte like everywhere else in this lib. Points are always *Point, but what
+the coordinates mean is up to the curve, e.g. the identity of an Edwards curve is the ordinary
+point (0,1) rather than a point with the Infinity flag, so compare points with Equal, and get the
+identity from Identity().
+
+ScalarMult, ScalarBaseMult and DoubleScalarMult may take shortcuts that depend on the scalar, use
+ScalarMultSecret for private keys, nonces and anything else that must not leak.
+
+An EllipticCurve can only be used as a Group if it has a base point G and its order N, which
+NewEllipticCurve and Validate make sure of. Toy curves built from a struct literal often have
+neither, Generator and Order panic on those.
+
+Points from outside, e.g. a peer's public key, go through ValidatePoint before they're used.
+UnmarshalPoint of EllipticCurve only checks that the point is on the curve, and on a curve with a
+cofactor a point on the curve can still have small order.
+*/
+type Group interface {
+	Identity() *Point                                               // the neutral element
+	Generator() *Point                                              // the base point G
+	Order() *big.Int                                                // the order of G
+	Add(p1, p2 *Point) *Point                                       // p1+p2
+	Negate(p *Point) *Point                                         // -p
+	Equal(p1, p2 *Point) bool                                       // p1 == p2
+	IsOnCurve(p *Point) bool                                        // p is a point of the group
+	ScalarMult(n []byte, p *Point) *Point                           // np
+	ScalarBaseMult(n []byte) *Point                                 // nG
+	DoubleScalarMult(a []byte, p *Point, b []byte, q *Point) *Point // ap+bq, a and b public
+	ScalarMultSecret(n []byte, p *Point) *Point                     // np, same operations for every n
+	ValidatePoint(p *Point) error                                   // p is in the subgroup generated by G
+	MarshalPoint(p *Point) []byte                                   // encoding of p
+	UnmarshalPoint(data []byte) (*Point, error)                     // decoding, at least on the curve
+}
+
+var (
+	_ Group = (*EllipticCurve)(nil)
+	_ Group = (*EdwardsCurve)(nil)
+)
+
+// Identity returns the point at infinity, the identity of the group.
+func (curve *EllipticCurve) Identity() *Point {
+	return Infinity()
+}
+
+// Generator returns a copy of the base point G, it panics if the curve has none.
+func (curve *EllipticCurve) Generator() *Point {
+	if curve.G == nil {
+		panic("core: curve has no base point G, it can't be used as a Group")
+	}
+	return copyPoint(curve.G)
+}
+
+// Order returns a copy of N, the order of G, it panics if the curve has none.
+func (curve *EllipticCurve) Order() *big.Int {
+	if curve.N == nil {
+		panic("core: curve has no order N, it can't be used as a Group")
+	}
+	return new(big.Int).Set(curve.N)
+}
+
+// ScalarMultSecret returns np for a secret n, with ScalarMultLadder.
+func (curve *EllipticCurve) ScalarMultSecret(n []byte, p *Point) *Point {
+	return curve.ScalarMultLadder(n, p)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testGroups are the Group implementations, including a toy curve where G generates all 3566 points.
+func testGroups() []Group {
+	toy := newSmallCurve(14, 19, 3623)
+	toy.G, toy.N = pt(6, 730), big.NewInt(3566)
+	return []Group{P256(), toy, Ed25519()}
+}
+
+// TestGroupLaws checks the same properties through the interface on every implementation.
+func TestGroupLaws(t *testing.T) {
+	for _, g := range testGroups() {
+		G := g.Generator()
+		O := g.Identity()
+		assert.True(t, g.IsOnCurve(G))
+		assert.True(t, g.Equal(g.Add(G, O), G))
+		assert.True(t, g.Equal(g.Add(G, g.Negate(G)), O))
+		assert.True(t, g.Equal(g.ScalarBaseMult(g.Order().Bytes()), O))
+		for i := 0; i < 5; i++ {
+			a, _ := rand.Int(rand.Reader, g.Order())
+			b, _ := rand.Int(rand.Reader, g.Order())
+			aG := g.ScalarBaseMult(a.Bytes())
+			bG := g.ScalarMult(b.Bytes(), G)
+			sum := new(big.Int).Add(a, b)
+			assert.True(t, g.Equal(g.Add(aG, bG), g.ScalarBaseMult(sum.Bytes())))
+			assert.True(t, g.Equal(g.DoubleScalarMult(a.Bytes(), G, b.Bytes(), G), g.ScalarBaseMult(sum.Bytes())))
+			assert.True(t, g.Equal(g.ScalarMultSecret(a.Bytes(), G), aG))
+			q, err := g.UnmarshalPoint(g.MarshalPoint(aG))
+			assert.NoError(t, err)
+			assert.True(t, g.Equal(aG, q))
+		}
+	}
+}
+
+// TestGroupRequiresBasePoint checks that a curve without G and N panics with a clear message.
+func TestGroupRequiresBasePoint(t *testing.T) {
+	curve := newSmallCurve(14, 19, 3623)
+	assert.PanicsWithValue(t, "core: curve has no base point G, it can't be used as a Group", func() { curve.Generator() })
+	assert.PanicsWithValue(t, "core: curve has no order N, it can't be used as a Group", func() { curve.Order() })
+	assert.Error(t, curve.Validate())
+}
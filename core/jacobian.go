@@ -0,0 +1,483 @@
+package core
+
+import (
+	"ecc/field"
+	"math/big"
+)
+
+/*
+jacobianPoint represents a point in Jacobian projective coordinates (X:Y:Z), which corresponds
+to the affine point (X/Z^2, Y/Z^3). The point at infinity is any point with Z=0.
+
+The advantage over affine coordinates is that add and double don't need a ModInverse, which
+is by far the most expensive field operation. We only pay for one inversion when converting
+back to affine at the end of a scalar multiplication.
+
+The coordinates are field.Elements of the curve's F_p, see primeField(), so the formulas below
+do Montgomery multiplications on fixed-size limbs instead of big.Int Mul and Mod.
+
+The formulas are from the Explicit-Formulas Database:
+https://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian.html
+*/
+type jacobianPoint struct {
+	X, Y, Z field.Element
+}
+
+// jacobianInfinity returns a new point at infinity (0:0:0).
+func jacobianInfinity() *jacobianPoint {
+	return &jacobianPoint{}
+}
+
+// isInfinity returns true if p is the point at infinity, i.e. Z=0.
+func (p *jacobianPoint) isInfinity() bool {
+	return p.Z == field.Element{}
+}
+
+// copyJacobian returns a copy of p.
+func copyJacobian(p *jacobianPoint) *jacobianPoint {
+	q := *p
+	return &q
+}
+
+// toJacobian converts an affine point to Jacobian coordinates by setting Z=1.
+func (curve *EllipticCurve) toJacobian(p *Point) *jacobianPoint {
+	if p.Infinity {
+		return jacobianInfinity()
+	}
+	fp := curve.primeField()
+	ret := &jacobianPoint{}
+	fp.SetBigInt(&ret.X, p.X)
+	fp.SetBigInt(&ret.Y, p.Y)
+	fp.SetOne(&ret.Z)
+	return ret
+}
+
+// toAffine converts p back to affine coordinates (X/Z^2, Y/Z^3), this costs one inversion.
+func (curve *EllipticCurve) toAffine(p *jacobianPoint) *Point {
+	if p.isInfinity() {
+		return Infinity()
+	}
+	fp := curve.primeField()
+	zInv := fp.Inverse(new(field.Element), &p.Z)
+	curve.count(opInverse)
+	zInv2 := curve.fieldSqr(new(field.Element), zInv)
+	x := curve.fieldMul(new(field.Element), &p.X, zInv2)
+	y := curve.fieldMul(new(field.Element), &p.Y, zInv2)
+	curve.fieldMul(y, y, zInv)
+	return &Point{X: fp.BigInt(x), Y: fp.BigInt(y)}
+}
+
+// aShape describes whether A has a special form that admits a faster doubling formula.
+type aShape int
+
+const (
+	aGeneric aShape = iota // any A, dbl-2007-bl
+	aMinus3                // A = -3 mod p like the NIST curves, dbl-2001-b
+	aZero                  // A = 0 like secp256k1, dbl-2009-l
+)
+
+/*
+setup derives what the arithmetic needs from the curve parameters: the field F_p, A as a field
+element, and the shape of A. It's done once and cached on the curve, constructors like P256()
+call it right away so that it happens at construction time, and curves built from a struct
+literal set up on first use instead. Don't change P or A after that.
+setup panics if p can't be used as a field.Field modulus, i.e. it's even or too large.
+*/
+func (curve *EllipticCurve) setup() {
+	curve.setupOnce.Do(func() {
+		fp, err := field.NewField(curve.P)
+		if err != nil {
+			panic("core: " + err.Error())
+		}
+		curve.fp = fp
+		fp.SetBigInt(&curve.a, curve.A)
+		a := new(big.Int).Mod(curve.A, curve.P)
+		minus3 := new(big.Int).Sub(curve.P, big.NewInt(3))
+		switch {
+		case len(a.Bits()) == 0:
+			curve.shape = aZero
+		case a.Cmp(minus3) == 0:
+			curve.shape = aMinus3
+		default:
+			curve.shape = aGeneric
+		}
+	})
+}
+
+// primeField returns F_p, the field of the coordinates.
+func (curve *EllipticCurve) primeField() *field.Field {
+	curve.setup()
+	return curve.fp
+}
+
+// aShape returns whether A = -3 or A = 0 mod p, see setup().
+func (curve *EllipticCurve) aShape() aShape {
+	curve.setup()
+	return curve.shape
+}
+
+// jacobianDouble returns 2p, using the fastest doubling formula available for A.
+func (curve *EllipticCurve) jacobianDouble(p *jacobianPoint) *jacobianPoint {
+	if p.isInfinity() {
+		return jacobianInfinity()
+	}
+	curve.count(opDouble)
+	switch curve.aShape() {
+	case aMinus3:
+		return curve.jacobianDoubleMinus3(p)
+	case aZero:
+		return curve.jacobianDoubleZero(p)
+	default:
+		return curve.jacobianDoubleGeneric(p)
+	}
+}
+
+/*
+jacobianDoubleGeneric returns 2p using dbl-2007-bl, which works for any A:
+XX = X1^2, YY = Y1^2, YYYY = YY^2, ZZ = Z1^2
+S = 2*((X1+YY)^2-XX-YYYY)
+M = 3*XX+A*ZZ^2
+X3 = M^2-2*S
+Y3 = M*(S-X3)-8*YYYY
+Z3 = (Y1+Z1)^2-YY-ZZ
+A point with y=0 gives Z3=0, i.e. the point at infinity, without special casing.
+*/
+func (curve *EllipticCurve) jacobianDoubleGeneric(p *jacobianPoint) *jacobianPoint {
+	xx := curve.fieldSqr(new(field.Element), &p.X)
+	yy := curve.fieldSqr(new(field.Element), &p.Y)
+	yyyy := curve.fieldSqr(new(field.Element), yy)
+	zz := curve.fieldSqr(new(field.Element), &p.Z)
+
+	s := curve.fieldAdd(new(field.Element), &p.X, yy)
+	curve.fieldSqr(s, s)
+	curve.fieldSub(s, s, xx)
+	curve.fieldSub(s, s, yyyy)
+	curve.fieldAdd(s, s, s)
+
+	m := curve.fieldAdd(new(field.Element), xx, xx)
+	curve.fieldAdd(m, m, xx)
+	t := curve.fieldSqr(new(field.Element), zz)
+	curve.fieldMul(t, t, &curve.a)
+	curve.fieldAdd(m, m, t)
+
+	ret := &jacobianPoint{}
+	curve.fieldSqr(&ret.X, m)
+	curve.fieldSub(&ret.X, &ret.X, s)
+	curve.fieldSub(&ret.X, &ret.X, s)
+
+	curve.fieldSub(&ret.Y, s, &ret.X)
+	curve.fieldMul(&ret.Y, &ret.Y, m)
+	curve.fieldAdd(yyyy, yyyy, yyyy) // 2*YYYY
+	curve.fieldAdd(yyyy, yyyy, yyyy) // 4*YYYY
+	curve.fieldAdd(yyyy, yyyy, yyyy) // 8*YYYY
+	curve.fieldSub(&ret.Y, &ret.Y, yyyy)
+
+	curve.fieldAdd(&ret.Z, &p.Y, &p.Z)
+	curve.fieldSqr(&ret.Z, &ret.Z)
+	curve.fieldSub(&ret.Z, &ret.Z, yy)
+	curve.fieldSub(&ret.Z, &ret.Z, zz)
+	return ret
+}
+
+/*
+jacobianDoubleMinus3 returns 2p using dbl-2001-b, which only works for A = -3:
+delta = Z1^2, gamma = Y1^2, beta = X1*gamma
+alpha = 3*(X1-delta)*(X1+delta)
+X3 = alpha^2-8*beta
+Z3 = (Y1+Z1)^2-gamma-delta
+Y3 = alpha*(4*beta-X3)-8*gamma^2
+Since 3*XX+A*ZZ^2 factors as 3*(X1-ZZ)*(X1+ZZ), this trades three squarings for one multiplication
+compared to dbl-2007-bl.
+*/
+func (curve *EllipticCurve) jacobianDoubleMinus3(p *jacobianPoint) *jacobianPoint {
+	delta := curve.fieldSqr(new(field.Element), &p.Z)
+	gamma := curve.fieldSqr(new(field.Element), &p.Y)
+	beta := curve.fieldMul(new(field.Element), &p.X, gamma)
+
+	alpha := curve.fieldSub(new(field.Element), &p.X, delta)
+	t := curve.fieldAdd(new(field.Element), &p.X, delta)
+	curve.fieldMul(alpha, alpha, t)
+	curve.fieldAdd(t, alpha, alpha)
+	curve.fieldAdd(alpha, alpha, t)
+
+	ret := &jacobianPoint{}
+	curve.fieldAdd(beta, beta, beta) // 2*beta
+	curve.fieldAdd(beta, beta, beta) // 4*beta
+	curve.fieldSqr(&ret.X, alpha)
+	curve.fieldSub(&ret.X, &ret.X, beta)
+	curve.fieldSub(&ret.X, &ret.X, beta)
+
+	curve.fieldAdd(&ret.Z, &p.Y, &p.Z)
+	curve.fieldSqr(&ret.Z, &ret.Z)
+	curve.fieldSub(&ret.Z, &ret.Z, gamma)
+	curve.fieldSub(&ret.Z, &ret.Z, delta)
+
+	curve.fieldSub(&ret.Y, beta, &ret.X)
+	curve.fieldMul(&ret.Y, &ret.Y, alpha)
+	curve.fieldSqr(gamma, gamma)
+	curve.fieldAdd(gamma, gamma, gamma) // 2*gamma^2
+	curve.fieldAdd(gamma, gamma, gamma) // 4*gamma^2
+	curve.fieldAdd(gamma, gamma, gamma) // 8*gamma^2
+	curve.fieldSub(&ret.Y, &ret.Y, gamma)
+	return ret
+}
+
+/*
+jacobianDoubleZero returns 2p using dbl-2009-l, which only works for A = 0:
+A = X1^2, B = Y1^2, C = B^2
+D = 2*((X1+B)^2-A-C)
+E = 3*A, F = E^2
+X3 = F-2*D
+Y3 = E*(D-X3)-8*C
+Z3 = 2*Y1*Z1
+*/
+func (curve *EllipticCurve) jacobianDoubleZero(p *jacobianPoint) *jacobianPoint {
+	a := curve.fieldSqr(new(field.Element), &p.X)
+	b := curve.fieldSqr(new(field.Element), &p.Y)
+	c := curve.fieldSqr(new(field.Element), b)
+
+	d := curve.fieldAdd(new(field.Element), &p.X, b)
+	curve.fieldSqr(d, d)
+	curve.fieldSub(d, d, a)
+	curve.fieldSub(d, d, c)
+	curve.fieldAdd(d, d, d)
+
+	e := curve.fieldAdd(new(field.Element), a, a)
+	curve.fieldAdd(e, e, a)
+
+	ret := &jacobianPoint{}
+	curve.fieldSqr(&ret.X, e)
+	curve.fieldSub(&ret.X, &ret.X, d)
+	curve.fieldSub(&ret.X, &ret.X, d)
+
+	curve.fieldSub(&ret.Y, d, &ret.X)
+	curve.fieldMul(&ret.Y, &ret.Y, e)
+	curve.fieldAdd(c, c, c) // 2*C
+	curve.fieldAdd(c, c, c) // 4*C
+	curve.fieldAdd(c, c, c) // 8*C
+	curve.fieldSub(&ret.Y, &ret.Y, c)
+
+	curve.fieldMul(&ret.Z, &p.Y, &p.Z)
+	curve.fieldAdd(&ret.Z, &ret.Z, &ret.Z)
+	return ret
+}
+
+/*
+jacobianAdd returns p1+p2 using add-2007-bl:
+Z1Z1 = Z1^2, Z2Z2 = Z2^2, U1 = X1*Z2Z2, U2 = X2*Z1Z1
+S1 = Y1*Z2*Z2Z2, S2 = Y2*Z1*Z1Z1
+H = U2-U1, I = (2*H)^2, J = H*I, r = 2*(S2-S1), V = U1*I
+X3 = r^2-J-2*V
+Y3 = r*(V-X3)-2*S1*J
+Z3 = ((Z1+Z2)^2-Z1Z1-Z2Z2)*H
+The formula breaks down when H=0, i.e. the points have the same affine x. Then p1=p2 if r=0,
+in which case we double, otherwise p1=-p2 and the sum is the point at infinity.
+*/
+func (curve *EllipticCurve) jacobianAdd(p1, p2 *jacobianPoint) *jacobianPoint {
+	if p1.isInfinity() {
+		return copyJacobian(p2)
+	}
+	if p2.isInfinity() {
+		return copyJacobian(p1)
+	}
+	ret, h, r := curve.addFormula(p1, p2)
+	if curve.primeField().IsZero(h) {
+		if curve.primeField().IsZero(r) {
+			return curve.jacobianDouble(p1)
+		}
+		curve.count(opAdd)
+		return jacobianInfinity()
+	}
+	curve.count(opAdd)
+	return ret
+}
+
+/*
+addFormula is the formula of jacobianAdd without any of the special cases: it always does the full
+computation and returns h and r so that the caller can tell whether the result is meaningful.
+If h=0 the result has Z3=0, i.e. it's the point at infinity, which is wrong if p1=p2, and if p1
+or p2 is the point at infinity the result is the point at infinity, which is wrong too.
+*/
+func (curve *EllipticCurve) addFormula(p1, p2 *jacobianPoint) (ret *jacobianPoint, h, r *field.Element) {
+	z1z1 := curve.fieldSqr(new(field.Element), &p1.Z)
+	z2z2 := curve.fieldSqr(new(field.Element), &p2.Z)
+	u1 := curve.fieldMul(new(field.Element), &p1.X, z2z2)
+	u2 := curve.fieldMul(new(field.Element), &p2.X, z1z1)
+	s1 := curve.fieldMul(new(field.Element), &p1.Y, &p2.Z)
+	curve.fieldMul(s1, s1, z2z2)
+	s2 := curve.fieldMul(new(field.Element), &p2.Y, &p1.Z)
+	curve.fieldMul(s2, s2, z1z1)
+
+	h = curve.fieldSub(new(field.Element), u2, u1)
+	r = curve.fieldSub(new(field.Element), s2, s1)
+	// r is doubled below, keep the difference for the caller
+	r2 := curve.fieldAdd(new(field.Element), r, r)
+	i := curve.fieldAdd(new(field.Element), h, h)
+	curve.fieldSqr(i, i)
+	j := curve.fieldMul(new(field.Element), h, i)
+	v := curve.fieldMul(new(field.Element), u1, i)
+
+	ret = &jacobianPoint{}
+	curve.fieldSqr(&ret.X, r2)
+	curve.fieldSub(&ret.X, &ret.X, j)
+	curve.fieldSub(&ret.X, &ret.X, v)
+	curve.fieldSub(&ret.X, &ret.X, v)
+
+	curve.fieldSub(&ret.Y, v, &ret.X)
+	curve.fieldMul(&ret.Y, &ret.Y, r2)
+	curve.fieldMul(s1, s1, j)
+	curve.fieldAdd(s1, s1, s1)
+	curve.fieldSub(&ret.Y, &ret.Y, s1)
+
+	curve.fieldAdd(&ret.Z, &p1.Z, &p2.Z)
+	curve.fieldSqr(&ret.Z, &ret.Z)
+	curve.fieldSub(&ret.Z, &ret.Z, z1z1)
+	curve.fieldSub(&ret.Z, &ret.Z, z2z2)
+	curve.fieldMul(&ret.Z, &ret.Z, h)
+	return ret, h, r
+}
+
+/*
+jacobianAddMixed returns p1+p2 where p2 has Z=1, using madd-2007-bl:
+Z1Z1 = Z1^2, U2 = X2*Z1Z1, S2 = Y2*Z1*Z1Z1
+H = U2-X1, HH = H^2, I = 4*HH, J = H*I, r = 2*(S2-Y1), V = X1*I
+X3 = r^2-J-2*V
+Y3 = r*(V-X3)-2*Y1*J
+Z3 = (Z1+H)^2-Z1Z1-HH
+Knowing Z2=1 saves four multiplications over jacobianAdd, which is worth it when one of the
+points comes from a precomputed table.
+*/
+func (curve *EllipticCurve) jacobianAddMixed(p1, p2 *jacobianPoint) *jacobianPoint {
+	if p1.isInfinity() {
+		return copyJacobian(p2)
+	}
+	if p2.isInfinity() {
+		return copyJacobian(p1)
+	}
+	z1z1 := curve.fieldSqr(new(field.Element), &p1.Z)
+	u2 := curve.fieldMul(new(field.Element), &p2.X, z1z1)
+	s2 := curve.fieldMul(new(field.Element), &p2.Y, &p1.Z)
+	curve.fieldMul(s2, s2, z1z1)
+
+	h := curve.fieldSub(new(field.Element), u2, &p1.X)
+	r := curve.fieldSub(new(field.Element), s2, &p1.Y)
+	if curve.primeField().IsZero(h) {
+		if curve.primeField().IsZero(r) {
+			return curve.jacobianDouble(p1)
+		}
+		curve.count(opAdd)
+		return jacobianInfinity()
+	}
+	curve.count(opAdd)
+	curve.fieldAdd(r, r, r)
+	hh := curve.fieldSqr(new(field.Element), h)
+	i := curve.fieldAdd(new(field.Element), hh, hh)
+	curve.fieldAdd(i, i, i)
+	j := curve.fieldMul(new(field.Element), h, i)
+	v := curve.fieldMul(new(field.Element), &p1.X, i)
+
+	ret := &jacobianPoint{}
+	curve.fieldSqr(&ret.X, r)
+	curve.fieldSub(&ret.X, &ret.X, j)
+	curve.fieldSub(&ret.X, &ret.X, v)
+	curve.fieldSub(&ret.X, &ret.X, v)
+
+	curve.fieldSub(&ret.Y, v, &ret.X)
+	curve.fieldMul(&ret.Y, &ret.Y, r)
+	curve.fieldMul(j, j, &p1.Y)
+	curve.fieldAdd(j, j, j)
+	curve.fieldSub(&ret.Y, &ret.Y, j)
+
+	curve.fieldAdd(&ret.Z, &p1.Z, h)
+	curve.fieldSqr(&ret.Z, &ret.Z)
+	curve.fieldSub(&ret.Z, &ret.Z, z1z1)
+	curve.fieldSub(&ret.Z, &ret.Z, hh)
+	return ret
+}
+
+// jacobianNegate returns -p, i.e. (X:-Y:Z).
+func (curve *EllipticCurve) jacobianNegate(p *jacobianPoint) *jacobianPoint {
+	ret := copyJacobian(p)
+	curve.primeField().Neg(&ret.Y, &p.Y)
+	return ret
+}
+
+/*
+conditionalSwap returns (p2, p1) if b is 1 and (p1, p2) if b is 0. It picks the points by indexing
+with b rather than branching on it, so that the control flow doesn't depend on b.
+*/
+func conditionalSwap(b uint, p1, p2 *jacobianPoint) (*jacobianPoint, *jacobianPoint) {
+	pair := [2]*jacobianPoint{p1, p2}
+	return pair[b], pair[b^1]
+}
+
+/*
+ladderDouble and ladderAdd are jacobianDouble and jacobianAdd for the Montgomery ladder, without the
+early returns for the point at infinity. Those make a step cheaper when R0 is still O, i.e. for
+every leading zero bit of the scalar, which gives away its bit length.
+
+Instead the full formula always runs. The doubling formulas take Z=0 to Z3=0 on their own, since
+Z3 is a multiple of Y1*Z1. The addition formula gives Z3=0 when an input is O, so the right result
+is picked afterwards by indexing, like conditionalSwap. The case p1=p2, where addFormula is wrong,
+can't happen in the ladder: R1-R0=P, so R0=R1 only if P=O, and then both are O anyway.
+*/
+
+// ladderDouble returns 2p, with the same operations whether or not p is the point at infinity.
+func (curve *EllipticCurve) ladderDouble(p *jacobianPoint) *jacobianPoint {
+	curve.count(opDouble)
+	switch curve.aShape() {
+	case aMinus3:
+		return curve.jacobianDoubleMinus3(p)
+	case aZero:
+		return curve.jacobianDoubleZero(p)
+	default:
+		return curve.jacobianDoubleGeneric(p)
+	}
+}
+
+// ladderAdd returns p1+p2 for p1 != p2, with the same operations whether or not p1 or p2 is O.
+func (curve *EllipticCurve) ladderAdd(p1, p2 *jacobianPoint) *jacobianPoint {
+	curve.count(opAdd)
+	sum, _, _ := curve.addFormula(p1, p2)
+	// 0: sum, 1: p2 is O so p1, 2 and 3: p1 is O so p2
+	choice := [4]*jacobianPoint{sum, p1, p2, p2}
+	return copyJacobian(choice[2*infinityBit(p1)+infinityBit(p2)])
+}
+
+// infinityBit returns 1 if p is the point at infinity and 0 otherwise.
+func infinityBit(p *jacobianPoint) uint {
+	var acc uint64
+	for _, l := range p.Z {
+		acc |= l
+	}
+	// acc|-acc has the top bit set iff acc != 0
+	return uint(((acc | -acc) >> 63) ^ 1)
+}
+
+/*
+Field arithmetic in F_p. They all set z to the result and return z, just like big.Int, so they
+can be chained. Going through them rather than calling the field.Field directly is what lets
+Stats count the field operations.
+*/
+
+// fieldMul sets z = x*y mod p.
+func (curve *EllipticCurve) fieldMul(z, x, y *field.Element) *field.Element {
+	curve.count(opMul)
+	return curve.primeField().Mul(z, x, y)
+}
+
+// fieldSqr sets z = x^2 mod p.
+func (curve *EllipticCurve) fieldSqr(z, x *field.Element) *field.Element {
+	curve.count(opSqr)
+	return curve.primeField().Square(z, x)
+}
+
+// fieldAdd sets z = x+y mod p.
+func (curve *EllipticCurve) fieldAdd(z, x, y *field.Element) *field.Element {
+	return curve.primeField().Add(z, x, y)
+}
+
+// fieldSub sets z = x-y mod p.
+func (curve *EllipticCurve) fieldSub(z, x, y *field.Element) *field.Element {
+	return curve.primeField().Sub(z, x, y)
+}
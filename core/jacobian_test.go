@@ -0,0 +1,143 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"ecc/field"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scaleJacobian returns p in Jacobian coordinates with Z=lambda, i.e. (X*lambda^2 : Y*lambda^3 : lambda).
+func scaleJacobian(curve *EllipticCurve, p *Point, lambda int64) *jacobianPoint {
+	if p.Infinity {
+		return jacobianInfinity()
+	}
+	var l field.Element
+	curve.primeField().SetBigInt(&l, big.NewInt(lambda))
+	l2 := curve.fieldSqr(new(field.Element), &l)
+	l3 := curve.fieldMul(new(field.Element), l2, &l)
+	jp := curve.toJacobian(p)
+	curve.fieldMul(&jp.X, &jp.X, l2)
+	curve.fieldMul(&jp.Y, &jp.Y, l3)
+	jp.Z = l
+	return jp
+}
+
+// smallCurvePoints returns every point on a curve with small p, including the point at infinity.
+func smallCurvePoints(curve *EllipticCurve) []*Point {
+	points := []*Point{Infinity()}
+	for x := int64(0); x < curve.P.Int64(); x++ {
+		for y := int64(0); y < curve.P.Int64(); y++ {
+			if p := pt(x, y); curve.IsOnCurve(p) {
+				points = append(points, p)
+			}
+		}
+	}
+	return points
+}
+
+func TestJacobianRoundTrip(t *testing.T) {
+	curve := smallCurves[1]
+	p := pt(6, 730)
+	assert.True(t, curve.Equal(curve.toAffine(curve.toJacobian(p)), p))
+	assert.True(t, curve.Equal(curve.toAffine(scaleJacobian(curve, p, 1234)), p))
+	assert.True(t, curve.toAffine(jacobianInfinity()).Infinity)
+	assert.True(t, curve.toJacobian(Infinity()).isInfinity())
+}
+
+// TestJacobianSmallCurves checks jacobianAdd and jacobianDouble against the affine Add on every pair of points.
+func TestJacobianSmallCurves(t *testing.T) {
+	for _, curve := range smallCurves {
+		if curve.P.Int64() >= 100 {
+			continue
+		}
+		points := smallCurvePoints(curve)
+		for _, p1 := range points {
+			j1 := scaleJacobian(curve, p1, 3)
+			assert.True(t, curve.Equal(curve.toAffine(curve.jacobianDouble(j1)), curve.Add(p1, p1)), curve.Name)
+			assert.True(t, curve.Equal(curve.toAffine(curve.jacobianNegate(j1)), curve.Negate(p1)), curve.Name)
+			for _, p2 := range points {
+				j2 := scaleJacobian(curve, p2, 5)
+				assert.True(t, curve.Equal(curve.toAffine(curve.jacobianAdd(j1, j2)), curve.Add(p1, p2)), curve.Name)
+				assert.True(t, curve.Equal(curve.toAffine(curve.jacobianAddMixed(j1, curve.toJacobian(p2))), curve.Add(p1, p2)), curve.Name)
+			}
+		}
+	}
+}
+
+func TestAShape(t *testing.T) {
+	assert.Equal(t, aGeneric, smallCurves[0].aShape())
+	assert.Equal(t, aGeneric, smallCurves[2].aShape()) // B=0 doesn't matter, only A does
+	assert.Equal(t, aMinus3, smallCurves[3].aShape())
+	assert.Equal(t, aZero, smallCurves[4].aShape())
+	assert.Equal(t, aMinus3, P256().aShape())
+	// A=p-3 is the same as A=-3
+	assert.Equal(t, aMinus3, newSmallCurve(26, 5, 29).aShape())
+}
+
+// TestJacobianDoubleSpecialized checks the specialized doubling formulas against the generic one.
+func TestJacobianDoubleSpecialized(t *testing.T) {
+	for _, curve := range []*EllipticCurve{smallCurves[3], smallCurves[4]} {
+		for _, p := range smallCurvePoints(curve) {
+			jp := scaleJacobian(curve, p, 6)
+			want := curve.toAffine(curve.jacobianDoubleGeneric(jp))
+			assert.True(t, curve.Equal(curve.toAffine(curve.jacobianDouble(jp)), want), curve.Name)
+		}
+	}
+	curve := P256()
+	jp := scaleJacobian(curve, curve.G, 9)
+	assert.True(t, curve.Equal(curve.toAffine(curve.jacobianDoubleMinus3(jp)), curve.toAffine(curve.jacobianDoubleGeneric(jp))))
+	curve = Secp256k1()
+	assert.True(t, curve.IsOnCurve(curve.G))
+	jp = scaleJacobian(curve, curve.G, 9)
+	assert.True(t, curve.Equal(curve.toAffine(curve.jacobianDoubleZero(jp)), curve.toAffine(curve.jacobianDoubleGeneric(jp))))
+}
+
+func TestJacobianLarge(t *testing.T) {
+	p256 := elliptic.P256()
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	p1X, p1Y := p256.ScalarBaseMult(b)
+	_, _ = rand.Read(b)
+	p2X, p2Y := p256.ScalarBaseMult(b)
+	sumX, sumY := p256.Add(p1X, p1Y, p2X, p2Y)
+	dblX, dblY := p256.Double(p1X, p1Y)
+
+	curve := P256()
+	j1 := scaleJacobian(curve, &Point{X: p1X, Y: p1Y}, 7)
+	j2 := scaleJacobian(curve, &Point{X: p2X, Y: p2Y}, 11)
+	assert.True(t, curve.Equal(curve.toAffine(curve.jacobianAdd(j1, j2)), &Point{X: sumX, Y: sumY}))
+	assert.True(t, curve.Equal(curve.toAffine(curve.jacobianDouble(j1)), &Point{X: dblX, Y: dblY}))
+	assert.True(t, curve.toAffine(curve.jacobianAdd(j1, curve.jacobianNegate(j1))).Infinity)
+}
+
+func benchmarkDouble(b *testing.B, curve *EllipticCurve, double func(*jacobianPoint) *jacobianPoint) {
+	jp := scaleJacobian(curve, curve.G, 9)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		jp = double(jp)
+	}
+}
+
+func BenchmarkDoubleGenericP256(b *testing.B) {
+	curve := P256()
+	benchmarkDouble(b, curve, curve.jacobianDoubleGeneric)
+}
+
+func BenchmarkDoubleMinus3P256(b *testing.B) {
+	curve := P256()
+	benchmarkDouble(b, curve, curve.jacobianDoubleMinus3)
+}
+
+func BenchmarkDoubleGenericSecp256k1(b *testing.B) {
+	curve := Secp256k1()
+	benchmarkDouble(b, curve, curve.jacobianDoubleGeneric)
+}
+
+func BenchmarkDoubleZeroSecp256k1(b *testing.B) {
+	curve := Secp256k1()
+	benchmarkDouble(b, curve, curve.jacobianDoubleZero)
+}
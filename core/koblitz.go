@@ -0,0 +1,43 @@
+package core
+
+/*
+The Koblitz curves from SEC2 (https://www.secg.org/sec2-v2.pdf) section 2. They have A=0, so doubling
+uses dbl-2009-l, and an efficiently computable endomorphism, which we don't use.
+None of them can be represented by crypto/elliptic, which assumes A=-3.
+*/
+
+// Secp192k1 returns the curve secp192k1, y^2 = x^3+3 over p = 2^192-2^32-4553.
+func Secp192k1() *EllipticCurve {
+	return curveFromHex("secp192k1", 192,
+		"fffffffffffffffffffffffffffffffffffffffeffffee37",
+		"0",
+		"3",
+		"fffffffffffffffffffffffe26f2fc170f69466a74defd8d",
+		"db4ff10ec057e9ae26b07d0280b7f4341da5d1b1eae06c7d",
+		"9b2f2f6d9c5628a7844163d015be86344082aa88d95e2f9d")
+}
+
+/*
+Secp224k1 returns the curve secp224k1, y^2 = x^3+5 over p = 2^224-2^32-6803.
+Note that N is 225 bits, larger than p, so scalars can be one bit longer than coordinates.
+*/
+func Secp224k1() *EllipticCurve {
+	return curveFromHex("secp224k1", 224,
+		"fffffffffffffffffffffffffffffffffffffffffffffffeffffe56d",
+		"0",
+		"5",
+		"010000000000000000000000000001dce8d2ec6184caf0a971769fb1f7",
+		"a1455b334df099df30fc28a169a467e9e47075a90f7e650eb6b7a45c",
+		"7e089fed7fba344282cafbd6f7e319f7c0b0bd59e2ca4bdb556d61a5")
+}
+
+// Secp256k1 returns the curve secp256k1 used by Bitcoin, y^2 = x^3+7 over p = 2^256-2^32-977.
+func Secp256k1() *EllipticCurve {
+	return curveFromHex("secp256k1", 256,
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+		"0",
+		"7",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hexPoint(x, y string) *Point {
+	p := &Point{}
+	p.X, _ = new(big.Int).SetString(x, 16)
+	p.Y, _ = new(big.Int).SetString(y, 16)
+	return p
+}
+
+func TestKoblitzParams(t *testing.T) {
+	for _, curve := range []*EllipticCurve{Secp192k1(), Secp224k1(), Secp256k1()} {
+		assert.Equal(t, aZero, curve.aShape(), curve.Name)
+		assert.True(t, curve.IsOnCurve(curve.G), curve.Name)
+		assert.True(t, curve.N.ProbablyPrime(20), curve.Name)
+		assert.True(t, curve.ScalarMult(curve.N.Bytes(), curve.G).Infinity, curve.Name)
+		assert.True(t, curve.ScalarBaseMult(curve.N.Bytes()).Infinity, curve.Name)
+	}
+}
+
+// TestKoblitzVectors checks small multiples of G for secp256k1 and key pairs generated by openssl.
+func TestKoblitzVectors(t *testing.T) {
+	for _, c := range []struct {
+		curve *EllipticCurve
+		k     string
+		kG    *Point
+	}{
+		{Secp256k1(), "2", hexPoint(
+			"c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+			"1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a")},
+		{Secp256k1(), "3", hexPoint(
+			"f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+			"388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672")},
+		{Secp256k1(), "e722916aa7dcfae333e315f2368e978bd39fc2d9a960966f297a1e018bed8f9b", hexPoint(
+			"f96aa8627a63a1c477d5ab16de10b505769e8473be3a24cff76e913eb421f5f8",
+			"691fb9f10922d83d387d91ee7ecda2b0a4eba8bb72a02397093a07437aafafcb")},
+		{Secp224k1(), "a668f053c63f5f02d514fd64943196243f09f7a35f2e1681ddb90808", hexPoint(
+			"09f3402527c6ee5160620c60e2bc97e0568065596a82eff24e123a0a",
+			"2158e1e1088939257a0cfd56f36fed68ee6724fb7260533ae588d7a9")},
+		{Secp192k1(), "5b4f73c3b5fd544d3d5c9cbab8c8a5dfbae985436a27bd8d", hexPoint(
+			"a6d856ea642554da060e441b7b8cb7fb9f43b90717714440",
+			"a148f33bfe3ff7584dd072942170b9725c6f9ab53e6e1945")},
+	} {
+		k, _ := new(big.Int).SetString(c.k, 16)
+		assert.True(t, c.curve.Equal(c.curve.ScalarBaseMult(k.Bytes()), c.kG), c.curve.Name)
+		assert.True(t, c.curve.Equal(c.curve.ScalarMultLadder(k.Bytes(), c.curve.G), c.kG), c.curve.Name)
+		assert.True(t, c.curve.Equal(c.curve.ScalarMultWNAF(k.Bytes(), c.curve.G, 5), c.kG), c.curve.Name)
+	}
+}
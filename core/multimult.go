@@ -0,0 +1,94 @@
+package core
+
+import (
+	"math/big"
+)
+
+/*
+DoubleScalarMult calculates aP+bQ using Shamir's trick. Instead of two separate scalar
+multiplications, go through the bits of a and b together from the most significant bit, doubling
+once per bit and adding P, Q or the precomputed P+Q depending on the pair of bits.
+That's one set of doublings instead of two, and about 3/4 of the bit pairs need an addition,
+compared to 1/2+1/2 for two double and adds.
+
+This is the computation at the heart of signature verification, e.g. sG+eX for Schnorr.
+Note that this is vulnerable to timing analysis, only use it with public scalars.
+*/
+func (curve *EllipticCurve) DoubleScalarMult(a []byte, p *Point, b []byte, q *Point) *Point {
+	sa := new(big.Int).SetBytes(a)
+	sb := new(big.Int).SetBytes(b)
+	jp := curve.toJacobian(p)
+	jq := curve.toJacobian(q)
+	// table[bitA + 2*bitB]
+	table := [4]*jacobianPoint{nil, jp, jq, curve.jacobianAdd(jp, jq)}
+	bits := sa.BitLen()
+	if sb.BitLen() > bits {
+		bits = sb.BitLen()
+	}
+	ret := jacobianInfinity()
+	for i := bits - 1; i >= 0; i-- {
+		ret = curve.jacobianDouble(ret)
+		if j := sa.Bit(i) + 2*sb.Bit(i); j != 0 {
+			ret = curve.jacobianAdd(ret, table[j])
+		}
+	}
+	return curve.toAffine(ret)
+}
+
+// strausWindow is the number of bits of each scalar handled at once by MultiScalarMult.
+const strausWindow = 4
+
+/*
+MultiScalarMult calculates the sum of scalars[i]*points[i] using Straus' interleaving method.
+For every point precompute 1P, 2P, ..., 15P, then go through all the scalars together 4 bits at a
+time from the most significant end: double the running sum 4 times and add the table entry
+for each scalar's 4-bit digit. All the points share the same doublings, so the cost is one
+scalar multiplication's worth of doublings plus about one addition per digit per point.
+This is the right method for a handful of points, since the tables cost 14 additions per point.
+For hundreds of points, use MultiScalarMultPippenger instead.
+
+scalars and points must have the same length.
+Note that this is vulnerable to timing analysis, only use it with public scalars.
+*/
+func (curve *EllipticCurve) MultiScalarMult(scalars [][]byte, points []*Point) *Point {
+	if len(scalars) != len(points) {
+		panic("core: MultiScalarMult needs as many scalars as points")
+	}
+	ks := make([]*big.Int, len(scalars))
+	tables := make([][]*jacobianPoint, len(points))
+	bits := 0
+	for i := range scalars {
+		ks[i] = new(big.Int).SetBytes(scalars[i])
+		if ks[i].BitLen() > bits {
+			bits = ks[i].BitLen()
+		}
+		// tables[i][j] = (j+1)*points[i]
+		tables[i] = make([]*jacobianPoint, 1<<strausWindow-1)
+		tables[i][0] = curve.toJacobian(points[i])
+		for j := 1; j < len(tables[i]); j++ {
+			tables[i][j] = curve.jacobianAdd(tables[i][j-1], tables[i][0])
+		}
+	}
+	windows := (bits + strausWindow - 1) / strausWindow
+	ret := jacobianInfinity()
+	for w := windows - 1; w >= 0; w-- {
+		for j := 0; j < strausWindow; j++ {
+			ret = curve.jacobianDouble(ret)
+		}
+		for i, k := range ks {
+			if d := window(k, w*strausWindow, strausWindow); d != 0 {
+				ret = curve.jacobianAdd(ret, tables[i][d-1])
+			}
+		}
+	}
+	return curve.toAffine(ret)
+}
+
+// window returns the width bits of k starting from bit start, i.e. (k >> start) mod 2^width.
+func window(k *big.Int, start, width int) uint {
+	var d uint
+	for j := 0; j < width; j++ {
+		d |= k.Bit(start+j) << uint(j)
+	}
+	return d
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveMultiScalarMult is the sum of scalars[i]*points[i] computed one scalar multiplication at a time.
+func naiveMultiScalarMult(curve *EllipticCurve, scalars [][]byte, points []*Point) *Point {
+	ret := Infinity()
+	for i := range scalars {
+		ret = curve.Add(ret, curve.ScalarMult(scalars[i], points[i]))
+	}
+	return ret
+}
+
+// randomPoints returns count random multiples of G and count random 32 byte scalars.
+func randomPoints(curve *EllipticCurve, count int) ([][]byte, []*Point) {
+	scalars := make([][]byte, count)
+	points := make([]*Point, count)
+	for i := range points {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		points[i] = curve.ScalarBaseMult(b)
+		scalars[i] = make([]byte, 32)
+		_, _ = rand.Read(scalars[i])
+	}
+	return scalars, points
+}
+
+func TestDoubleScalarMult(t *testing.T) {
+	curve := smallCurves[1]
+	p := pt(6, 730)
+	q := pt(0, 351)
+	for a := int64(0); a < 50; a += 3 {
+		for b := int64(0); b < 50; b += 5 {
+			ab, bb := big.NewInt(a).Bytes(), big.NewInt(b).Bytes()
+			want := curve.Add(curve.ScalarMult(ab, p), curve.ScalarMult(bb, q))
+			assert.True(t, curve.Equal(curve.DoubleScalarMult(ab, p, bb, q), want), "a=%d b=%d", a, b)
+		}
+	}
+	// aP + bP and aP + b(-P)
+	want := curve.ScalarMult(big.NewInt(12).Bytes(), p)
+	assert.True(t, curve.Equal(curve.DoubleScalarMult([]byte{5}, p, []byte{7}, p), want))
+	assert.True(t, curve.DoubleScalarMult([]byte{7}, p, []byte{7}, curve.Negate(p)).Infinity)
+
+	p256 := P256()
+	scalars, points := randomPoints(p256, 2)
+	want = naiveMultiScalarMult(p256, scalars, points)
+	assert.True(t, p256.Equal(p256.DoubleScalarMult(scalars[0], points[0], scalars[1], points[1]), want))
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	curve := P256()
+	for _, count := range []int{0, 1, 2, 5, 16} {
+		scalars, points := randomPoints(curve, count)
+		want := naiveMultiScalarMult(curve, scalars, points)
+		assert.True(t, curve.Equal(curve.MultiScalarMult(scalars, points), want), "count=%d", count)
+	}
+	// scalars of different lengths, the point at infinity and cancelling terms
+	scalars := [][]byte{{1, 0, 0}, {3}, {9, 9}, {1, 0, 0}}
+	points := []*Point{curve.G, Infinity(), curve.G, curve.Negate(curve.G)}
+	want := curve.ScalarMult([]byte{9, 9}, curve.G)
+	assert.True(t, curve.Equal(curve.MultiScalarMult(scalars, points), want))
+	assert.Panics(t, func() { curve.MultiScalarMult(scalars, points[1:]) })
+}
+
+func BenchmarkDoubleScalarMult(b *testing.B) {
+	curve := P256()
+	scalars, points := randomPoints(curve, 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curve.DoubleScalarMult(scalars[0], points[0], scalars[1], points[1])
+	}
+}
+
+func BenchmarkMultiScalarMult16(b *testing.B) {
+	curve := P256()
+	scalars, points := randomPoints(curve, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curve.MultiScalarMult(scalars, points)
+	}
+}
+
+func TestPippengerWindow(t *testing.T) {
+	assert.Equal(t, 2, pippengerWindow(0))
+	assert.Equal(t, 2, pippengerWindow(10))
+	assert.Equal(t, 5, pippengerWindow(100))
+	assert.Equal(t, 8, pippengerWindow(1000))
+	assert.Equal(t, 16, pippengerWindow(1<<30))
+}
+
+func TestMultiScalarMultPippenger(t *testing.T) {
+	curve := P256()
+	for _, count := range []int{0, 1, 3, 40, 150} {
+		scalars, points := randomPoints(curve, count)
+		want := curve.MultiScalarMult(scalars, points)
+		assert.True(t, curve.Equal(curve.MultiScalarMultPippenger(scalars, points, 1), want), "count=%d", count)
+		assert.True(t, curve.Equal(curve.MultiScalarMultPippenger(scalars, points, 4), want), "count=%d", count)
+	}
+	// repeated points land in the same bucket, and cancelling terms
+	scalars := [][]byte{{1, 0, 0}, {3}, {9, 9}, {1, 0, 0}, {5}}
+	points := []*Point{curve.G, Infinity(), curve.G, curve.Negate(curve.G), curve.G}
+	want := curve.ScalarMult([]byte{9, 14}, curve.G)
+	assert.True(t, curve.Equal(curve.MultiScalarMultPippenger(scalars, points, 1), want))
+	assert.True(t, curve.Equal(curve.MultiScalarMultPippenger(scalars, points, 3), want))
+	assert.Panics(t, func() { curve.MultiScalarMultPippenger(scalars, points[1:], 1) })
+
+	small := smallCurves[1]
+	p := pt(6, 730)
+	q := pt(0, 351)
+	scalars = [][]byte{{200}, {0x0f, 0xff}, {7}}
+	points = []*Point{p, q, p}
+	want = naiveMultiScalarMult(small, scalars, points)
+	assert.True(t, small.Equal(small.MultiScalarMultPippenger(scalars, points, 2), want))
+}
+
+func benchmarkMSM(b *testing.B, count int, msm func(*EllipticCurve, [][]byte, []*Point) *Point) {
+	curve := P256()
+	scalars, points := randomPoints(curve, count)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msm(curve, scalars, points)
+	}
+}
+
+func BenchmarkMSMStraus256(b *testing.B) {
+	benchmarkMSM(b, 256, func(c *EllipticCurve, s [][]byte, p []*Point) *Point { return c.MultiScalarMult(s, p) })
+}
+
+func BenchmarkMSMPippenger256(b *testing.B) {
+	benchmarkMSM(b, 256, func(c *EllipticCurve, s [][]byte, p []*Point) *Point { return c.MultiScalarMultPippenger(s, p, 1) })
+}
+
+func BenchmarkMSMPippenger256Parallel(b *testing.B) {
+	benchmarkMSM(b, 256, func(c *EllipticCurve, s [][]byte, p []*Point) *Point { return c.MultiScalarMultPippenger(s, p, 4) })
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"math/big"
+)
+
+/*
+naf returns the non-adjacent form (NAF) of k, with the lowest digit first. Every digit is 0, 1 or
+-1 and no two adjacent digits are both non-zero, so on average only 1/3 of the digits are non-zero,
+compared to 1/2 for the binary expansion. The NAF is at most one digit longer than k.
+
+Going from the lowest bit, when k is odd pick the digit d = 2 - (k mod 4), which is 1 or -1, such
+that k-d is divisible by 4, which forces the next digit to be 0. See Hankerson et al. Algorithm 3.30.
+*/
+func naf(k *big.Int) []int8 {
+	k = new(big.Int).Set(k)
+	digits := make([]int8, 0, k.BitLen()+1)
+	for k.Sign() > 0 {
+		var d int8
+		if k.Bit(0) == 1 {
+			d = 2 - int8(k.Bits()[0]&3)
+			k.Sub(k, big.NewInt(int64(d)))
+		}
+		digits = append(digits, d)
+		k.Rsh(k, 1)
+	}
+	return digits
+}
+
+/*
+wnaf returns the width-w NAF of k, with the lowest digit first. Every non-zero digit is odd and
+less than 2^(w-1) in absolute value, and among any w consecutive digits at most one is non-zero,
+so on average 1/(w+1) of the digits are non-zero. wnaf(k, 2) is the same as naf(k).
+
+When k is odd, pick the digit d = k mods 2^w, the residue of k mod 2^w in (-2^(w-1), 2^(w-1)),
+so that k-d is divisible by 2^w. See Hankerson et al. Algorithm 3.35.
+Since digits are int8, w has to be between 2 and 8.
+*/
+func wnaf(k *big.Int, w uint) []int8 {
+	if w < 2 || w > 8 {
+		panic("core: wNAF width must be between 2 and 8")
+	}
+	k = new(big.Int).Set(k)
+	mask := big.Word(1)<<w - 1
+	half := int(1) << (w - 1)
+	digits := make([]int8, 0, k.BitLen()+1)
+	for k.Sign() > 0 {
+		var d int
+		if k.Bit(0) == 1 {
+			d = int(k.Bits()[0] & mask)
+			if d >= half {
+				d -= 2 * half
+			}
+			k.Sub(k, big.NewInt(int64(d)))
+		}
+		digits = append(digits, int8(d))
+		k.Rsh(k, 1)
+	}
+	return digits
+}
+
+/*
+oddMultiples returns the table P, 3P, 5P, ..., (2^(w-1)-1)P used by ScalarMultWNAF, the entry i
+being (2i+1)P. Only odd multiples are needed because every non-zero wNAF digit is odd, and
+negative digits are handled by negating the entry, which is free.
+*/
+func (curve *EllipticCurve) oddMultiples(p *jacobianPoint, w uint) []*jacobianPoint {
+	table := make([]*jacobianPoint, 1<<(w-2))
+	table[0] = p
+	if len(table) > 1 {
+		p2 := curve.jacobianDouble(p)
+		for i := 1; i < len(table); i++ {
+			table[i] = curve.jacobianAdd(table[i-1], p2)
+		}
+	}
+	return table
+}
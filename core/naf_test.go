@@ -0,0 +1,111 @@
+package core
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// digitsValue returns sum d_i 2^i for digits with the lowest digit first.
+func digitsValue(digits []int8) *big.Int {
+	v := new(big.Int)
+	for i := len(digits) - 1; i >= 0; i-- {
+		v.Lsh(v, 1)
+		v.Add(v, big.NewInt(int64(digits[i])))
+	}
+	return v
+}
+
+// nonZero returns the number of non-zero digits.
+func nonZero(digits []int8) int {
+	count := 0
+	for _, d := range digits {
+		if d != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func TestNAF(t *testing.T) {
+	n, _ := strconv.ParseInt("100110111001", 2, 32) // n=2489
+	assert.Equal(t, []int8{1, 0, 0, -1, 0, 0, -1, 0, 0, 1, 0, 1}, naf(big.NewInt(n)))
+
+	n, _ = strconv.ParseInt("10011110111001", 2, 32) // n=10169
+	assert.Equal(t, []int8{1, 0, 0, -1, 0, 0, -1, 0, 0, 0, 0, 1, 0, 1}, naf(big.NewInt(n)))
+
+	assert.Empty(t, naf(big.NewInt(0)))
+	assert.Equal(t, []int8{-1, 0, 0, 1}, naf(big.NewInt(7)))
+
+	for i := 0; i < 100; i++ {
+		k, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 256))
+		digits := naf(k)
+		assert.Equal(t, 0, digitsValue(digits).Cmp(k))
+		assert.LessOrEqual(t, len(digits), k.BitLen()+1)
+		for j, d := range digits {
+			assert.Contains(t, []int8{-1, 0, 1}, d)
+			if j > 0 {
+				assert.False(t, d != 0 && digits[j-1] != 0, "adjacent non-zero digits in NAF of %v", k)
+			}
+		}
+		assert.Equal(t, digits, wnaf(k, 2))
+	}
+}
+
+func TestWNAF(t *testing.T) {
+	for w := uint(2); w <= 8; w++ {
+		for i := 0; i < 100; i++ {
+			k, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 256))
+			digits := wnaf(k, w)
+			assert.Equal(t, 0, digitsValue(digits).Cmp(k))
+			assert.LessOrEqual(t, len(digits), k.BitLen()+1)
+			for j, d := range digits {
+				if d == 0 {
+					continue
+				}
+				assert.True(t, d%2 != 0, "even digit %d", d)
+				assert.Less(t, int(d), 1<<(w-1))
+				assert.Greater(t, int(d), -(1 << (w - 1)))
+				// the next w-1 digits are zero
+				for l := j + 1; l < j+int(w) && l < len(digits); l++ {
+					assert.Equal(t, int8(0), digits[l], "w=%d", w)
+				}
+			}
+		}
+	}
+	assert.Panics(t, func() { wnaf(big.NewInt(1), 1) })
+	assert.Panics(t, func() { wnaf(big.NewInt(1), 9) })
+}
+
+/*
+TestWNAFDensity checks that the average number of non-zero digits matches the theory: 1/(w+1) of
+the digits for random scalars, that's 1/3 for the NAF and 1/2 for plain binary.
+*/
+func TestWNAFDensity(t *testing.T) {
+	const samples = 200
+	for w := uint(2); w <= 8; w++ {
+		digits, nonZeros := 0, 0
+		for i := 0; i < samples; i++ {
+			k, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 256))
+			d := wnaf(k, w)
+			digits += len(d)
+			nonZeros += nonZero(d)
+		}
+		density := float64(nonZeros) / float64(digits)
+		assert.InDelta(t, 1/float64(w+1), density, 0.01, "w=%d", w)
+	}
+}
+
+func TestOddMultiples(t *testing.T) {
+	curve := smallCurves[1]
+	p := pt(6, 730)
+	table := curve.oddMultiples(curve.toJacobian(p), 5)
+	assert.Len(t, table, 8)
+	for i, q := range table {
+		want := curve.ScalarMult(big.NewInt(int64(2*i+1)).Bytes(), p)
+		assert.True(t, curve.Equal(curve.toAffine(q), want))
+	}
+}
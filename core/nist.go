@@ -0,0 +1,76 @@
+package core
+
+/*
+The NIST curves from FIPS 186-4, appendix D.1.2. They all have A=-3, so doubling uses dbl-2001-b,
+and cofactor 1. The parameters are in the hex form of the FIPS document, which is also how
+golang's crypto/elliptic and openssl ecparam -param_enc explicit -text print them.
+
+https://nvlpubs.nist.gov/nistpubs/FIPS/NIST.FIPS.186-4.pdf
+*/
+
+// P192 returns the curve P-192, also known as secp192r1 and prime192v1. p = 2^192-2^64-1.
+func P192() *EllipticCurve {
+	return curveFromHex("P-192", 192,
+		"fffffffffffffffffffffffffffffffeffffffffffffffff",
+		"-3",
+		"64210519e59c80e70fa7e9ab72243049feb8deecc146b9b1",
+		"ffffffffffffffffffffffff99def836146bc9b1b4d22831",
+		"188da80eb03090f67cbf20eb43a18800f4ff0afd82ff1012",
+		"07192b95ffc8da78631011ed6b24cdd573f977a11e794811")
+}
+
+/*
+P224 returns the curve P-224, also known as secp224r1. p = 2^224-2^96+1, which is 1 mod 2^96,
+so square roots go through Tonelli-Shanks.
+*/
+func P224() *EllipticCurve {
+	return curveFromHex("P-224", 224,
+		"ffffffffffffffffffffffffffffffff000000000000000000000001",
+		"-3",
+		"b4050a850c04b3abf54132565044b0b7d7bfd8ba270b39432355ffb4",
+		"ffffffffffffffffffffffffffff16a2e0b8f03e13dd29455c5c2a3d",
+		"b70e0cbd6bb4bf7f321390b94a03c1d356c21122343280d6115c1d21",
+		"bd376388b5f723fb4c22dfe6cd4375a05a07476444d5819985007e34")
+}
+
+/*
+Returns a curve that uses golang p256 curve parameters, which is based on FIPS 186-3, section D.2.3 (p.100). Per this guide, section D.1.2 specifies the curve equation:
+$$E:y^2 \equiv x^3-3x+b (mod p)$$
+
+Golang's implementation:
+https://cs.opensource.google/go/go/+/refs/tags/go1.16.6:src/crypto/elliptic/p256.go
+
+NIST publication:
+https://csrc.nist.gov/csrc/media/publications/fips/186/3/archive/2009-06-25/documents/fips_186-3.pdf
+*/
+func P256() *EllipticCurve {
+	return curveFromHex("P-256", 256,
+		"ffffffff00000001000000000000000000000000ffffffffffffffffffffffff",
+		"-3",
+		"5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b",
+		"ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551",
+		"6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296",
+		"4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5")
+}
+
+// P384 returns the curve P-384, also known as secp384r1. p = 2^384-2^128-2^96+2^32-1.
+func P384() *EllipticCurve {
+	return curveFromHex("P-384", 384,
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffff",
+		"-3",
+		"b3312fa7e23ee7e4988e056be3f82d19181d9c6efe8141120314088f5013875ac656398d8a2ed19d2a85c8edd3ec2aef",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffc7634d81f4372ddf581a0db248b0a77aecec196accc52973",
+		"aa87ca22be8b05378eb1c71ef320ad746e1d3b628ba79b9859f741e082542a385502f25dbf55296c3a545e3872760ab7",
+		"3617de4a96262c6f5d9e98bf9292dc29f8f41dbd289a147ce9da3113b5f0b8c00a60b1ce1d7e819d7a431d7c90ea0e5f")
+}
+
+// P521 returns the curve P-521, also known as secp521r1. p = 2^521-1, which needs all 9 limbs of a field.Element.
+func P521() *EllipticCurve {
+	return curveFromHex("P-521", 521,
+		"1ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"-3",
+		"051953eb9618e1c9a1f929a21a0b68540eea2da725b99b315f3b8b489918ef109e156193951ec7e937b1652c0bd3bb1bf073573df883d2c34f1ef451fd46b503f00",
+		"1fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffa51868783bf2f966b7fcc0148f709a5d03bb5c9b8899c47aebb6fb71e91386409",
+		"0c6858e06b70404e9cd9e3ecb662395b4429c648139053fb521f828af606b4d3dbaa14b5e77efe75928fe1dc127a2ffa8de3348b3c1856a429bf97e7e31c2e5bd66",
+		"11839296a789a3bc0045c8a5fb42c7d1bd998f54449579b446817afbd17273e662c97ee72995ef42640c550b9013fad0761353c7086a272c24088be94769fd16650")
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNISTParams checks that G is on the curve and has order N.
+func TestNISTParams(t *testing.T) {
+	for _, curve := range []*EllipticCurve{P192(), P224(), P256(), P384(), P521()} {
+		assert.Equal(t, curve.BitSize, curve.P.BitLen(), curve.Name)
+		assert.True(t, curve.IsOnCurve(curve.G), curve.Name)
+		assert.True(t, curve.ScalarMult(curve.N.Bytes(), curve.G).Infinity, curve.Name)
+		assert.False(t, curve.ScalarMult(new(big.Int).Sub(curve.N, big.NewInt(1)).Bytes(), curve.G).Infinity, curve.Name)
+		assert.True(t, curve.N.ProbablyPrime(20), curve.Name)
+		g, err := curve.PointFromX(curve.G.X, curve.G.Y.Bit(0) == 1)
+		assert.NoError(t, err, curve.Name)
+		assert.True(t, curve.Equal(g, curve.G), curve.Name)
+	}
+}
+
+// TestNISTAgainstGo compares parameters and random scalar multiplications with crypto/elliptic.
+func TestNISTAgainstGo(t *testing.T) {
+	for _, c := range []struct {
+		ours *EllipticCurve
+		gos  elliptic.Curve
+	}{
+		{P224(), elliptic.P224()},
+		{P256(), elliptic.P256()},
+		{P384(), elliptic.P384()},
+		{P521(), elliptic.P521()},
+	} {
+		curve, params := c.ours, c.gos.Params()
+		assert.Equal(t, params.Name, curve.Name)
+		assert.Equal(t, 0, params.P.Cmp(curve.P), curve.Name)
+		assert.Equal(t, 0, params.N.Cmp(curve.N), curve.Name)
+		assert.Equal(t, 0, params.B.Cmp(curve.B), curve.Name)
+		assert.True(t, curve.Equal(curve.G, &Point{X: params.Gx, Y: params.Gy}), curve.Name)
+		for i := 0; i < 5; i++ {
+			k, _ := rand.Int(rand.Reader, curve.N)
+			x, y := c.gos.ScalarBaseMult(k.Bytes())
+			assert.True(t, curve.Equal(curve.ScalarBaseMult(k.Bytes()), &Point{X: x, Y: y}), curve.Name)
+			k2, _ := rand.Int(rand.Reader, curve.N)
+			x2, y2 := c.gos.ScalarMult(x, y, k2.Bytes())
+			assert.True(t, curve.Equal(curve.ScalarMult(k2.Bytes(), &Point{X: x, Y: y}), &Point{X: x2, Y: y2}), curve.Name)
+		}
+	}
+}
+
+// TestP192 uses a key pair generated by openssl, since crypto/elliptic doesn't have P-192.
+func TestP192(t *testing.T) {
+	curve := P192()
+	priv, _ := new(big.Int).SetString("8e4306ea28e62432c27ae254b159c867d251b2af2cac5d67", 16)
+	pub := &Point{}
+	pub.X, _ = new(big.Int).SetString("688dbfdaa6085efa5e457a8d1d817c06c95c85c154b7bfff", 16)
+	pub.Y, _ = new(big.Int).SetString("8f54086f8275eaedc9285f5f63da107787fc3b1c8472b8f4", 16)
+	assert.True(t, curve.Equal(curve.ScalarBaseMult(priv.Bytes()), pub))
+	assert.True(t, curve.Equal(curve.ScalarMultLadder(priv.Bytes(), curve.G), pub))
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+/*
+pippengerWindow picks the window size c for n points. Each window costs about n additions to fill
+the buckets plus 2^(c+1) additions to sum them up, and there are 256/c windows for 256-bit scalars,
+so c should grow like log2(n). Subtracting 2 keeps 2^c a few times smaller than n, which is
+about where the two costs balance.
+*/
+func pippengerWindow(n int) int {
+	c := bits.Len(uint(n)) - 2
+	if c < 2 {
+		return 2
+	}
+	if c > 16 {
+		return 16
+	}
+	return c
+}
+
+/*
+MultiScalarMultPippenger calculates the sum of scalars[i]*points[i] using Pippenger's bucket method,
+which beats MultiScalarMult once there are more than a few dozen points.
+
+The scalars are cut into windows of c bits, with c chosen from the number of points by
+pippengerWindow. For every window, each point is added to the bucket of its scalar's c-bit digit,
+then the window sum is sum j*B_j over the buckets B_j. That's computed with a running sum from the
+top bucket down, which takes 2 additions per bucket instead of a scalar multiplication.
+Finally the window sums are combined by doubling c times between windows.
+There are no precomputed tables, every point costs one (mixed) addition per window.
+
+Windows are independent of each other, so if workers is more than 1 they are split across that
+many goroutines. workers <= 1 does everything in the calling goroutine.
+
+scalars and points must have the same length.
+Note that this is vulnerable to timing analysis, only use it with public scalars.
+*/
+func (curve *EllipticCurve) MultiScalarMultPippenger(scalars [][]byte, points []*Point, workers int) *Point {
+	if len(scalars) != len(points) {
+		panic("core: MultiScalarMultPippenger needs as many scalars as points")
+	}
+	c := pippengerWindow(len(points))
+	ks := make([]*big.Int, len(scalars))
+	jps := make([]*jacobianPoint, len(points))
+	maxBits := 0
+	for i := range scalars {
+		ks[i] = new(big.Int).SetBytes(scalars[i])
+		if ks[i].BitLen() > maxBits {
+			maxBits = ks[i].BitLen()
+		}
+		jps[i] = curve.toJacobian(points[i])
+	}
+	windows := (maxBits + c - 1) / c
+	sums := make([]*jacobianPoint, windows)
+	if workers <= 1 {
+		for w := range sums {
+			sums[w] = curve.pippengerWindowSum(ks, jps, w*c, c)
+		}
+	} else {
+		var wg sync.WaitGroup
+		next := make(chan int)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for w := range next {
+					sums[w] = curve.pippengerWindowSum(ks, jps, w*c, c)
+				}
+			}()
+		}
+		for w := range sums {
+			next <- w
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	ret := jacobianInfinity()
+	for w := windows - 1; w >= 0; w-- {
+		for j := 0; j < c; j++ {
+			ret = curve.jacobianDouble(ret)
+		}
+		ret = curve.jacobianAdd(ret, sums[w])
+	}
+	return curve.toAffine(ret)
+}
+
+// pippengerWindowSum returns the sum of d_i*points[i] where d_i is the c-bit digit of scalars[i] starting at bit start.
+func (curve *EllipticCurve) pippengerWindowSum(scalars []*big.Int, points []*jacobianPoint, start, c int) *jacobianPoint {
+	// buckets[j] is the sum of points whose digit is j+1
+	buckets := make([]*jacobianPoint, 1<<uint(c)-1)
+	for j := range buckets {
+		buckets[j] = jacobianInfinity()
+	}
+	for i, k := range scalars {
+		if d := window(k, start, c); d != 0 {
+			buckets[d-1] = curve.jacobianAddMixed(buckets[d-1], points[i])
+		}
+	}
+	// running holds B_top + ... + B_j, and sum accumulates running for every j,
+	// so that B_j gets added j times
+	running := jacobianInfinity()
+	sum := jacobianInfinity()
+	for j := len(buckets) - 1; j >= 0; j-- {
+		running = curve.jacobianAdd(running, buckets[j])
+		sum = curve.jacobianAdd(sum, running)
+	}
+	return sum
+}
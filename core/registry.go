@@ -0,0 +1,141 @@
+package core
+
+import (
+	"encoding/asn1"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+CurveInfo describes a curve in the registry: its canonical name, other names it's known by,
+its ASN.1 object identifier as used in SEC1 EC parameters and X.509, and a constructor.
+Names are case-insensitive, so "P-256", "p-256", "secp256r1" and "prime256v1" all find P-256.
+*/
+type CurveInfo struct {
+	Name    string                // canonical name, the Name of the curve New returns
+	Aliases []string              // other names, e.g. from SEC2 or ANSI X9.62
+	OID     asn1.ObjectIdentifier // nil if the curve has none
+	New     func() *EllipticCurve // returns a new instance of the curve
+}
+
+var registry = struct {
+	sync.RWMutex
+	byName map[string]*CurveInfo // lowercase name or alias
+	byOID  map[string]*CurveInfo // OID.String()
+	infos  []*CurveInfo
+}{byName: map[string]*CurveInfo{}, byOID: map[string]*CurveInfo{}}
+
+/*
+RegisterCurve adds a curve to the registry. It returns an error if the name, one of the aliases
+or the OID is already taken, in which case nothing is registered.
+The registry keeps a copy of info, so changing info afterwards doesn't change the registration.
+The built-in curves are registered by this package, see init().
+*/
+func RegisterCurve(info *CurveInfo) error {
+	if info.Name == "" || info.New == nil {
+		return errors.New("core: a registered curve needs a name and a constructor")
+	}
+	info = copyCurveInfo(info)
+	registry.Lock()
+	defer registry.Unlock()
+	names := append([]string{info.Name}, info.Aliases...)
+	for _, name := range names {
+		if _, ok := registry.byName[strings.ToLower(name)]; ok {
+			return errors.New("core: curve name " + name + " is already registered")
+		}
+	}
+	if info.OID != nil {
+		if _, ok := registry.byOID[info.OID.String()]; ok {
+			return errors.New("core: curve OID " + info.OID.String() + " is already registered")
+		}
+		registry.byOID[info.OID.String()] = info
+	}
+	for _, name := range names {
+		registry.byName[strings.ToLower(name)] = info
+	}
+	registry.infos = append(registry.infos, info)
+	return nil
+}
+
+/*
+CurveByName returns a new instance of the curve registered under name or one of its aliases.
+Every call builds a new curve, so setting Stats or changing parameters doesn't affect other callers.
+*/
+func CurveByName(name string) (*EllipticCurve, error) {
+	registry.RLock()
+	info, ok := registry.byName[strings.ToLower(name)]
+	registry.RUnlock()
+	if !ok {
+		return nil, errors.New("core: unknown curve " + name)
+	}
+	return info.New(), nil
+}
+
+// CurveByOID returns a new instance of the curve with the given object identifier.
+func CurveByOID(oid asn1.ObjectIdentifier) (*EllipticCurve, error) {
+	registry.RLock()
+	info, ok := registry.byOID[oid.String()]
+	registry.RUnlock()
+	if !ok {
+		return nil, errors.New("core: unknown curve OID " + oid.String())
+	}
+	return info.New(), nil
+}
+
+// Curves returns copies of the registered curves sorted by name.
+func Curves() []*CurveInfo {
+	registry.RLock()
+	ret := make([]*CurveInfo, len(registry.infos))
+	for i, info := range registry.infos {
+		ret[i] = copyCurveInfo(info)
+	}
+	registry.RUnlock()
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// copyCurveInfo returns a deep copy of info, the Aliases and the OID included.
+func copyCurveInfo(info *CurveInfo) *CurveInfo {
+	ret := *info
+	ret.Aliases = append([]string(nil), info.Aliases...)
+	if info.OID != nil {
+		ret.OID = append(asn1.ObjectIdentifier(nil), info.OID...)
+	}
+	return &ret
+}
+
+// init registers the built-in curves, with the OIDs from SEC2 appendix A.2 and RFC 5639 section 4.1.
+func init() {
+	secg := func(arc int) asn1.ObjectIdentifier { return asn1.ObjectIdentifier{1, 3, 132, 0, arc} }
+	brainpool := func(arc int) asn1.ObjectIdentifier { return asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, arc} }
+	for _, info := range []*CurveInfo{
+		{"P-192", []string{"secp192r1", "prime192v1"}, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 1}, P192},
+		{"P-224", []string{"secp224r1"}, secg(33), P224},
+		{"P-256", []string{"secp256r1", "prime256v1"}, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, P256},
+		{"P-384", []string{"secp384r1"}, secg(34), P384},
+		{"P-521", []string{"secp521r1"}, secg(35), P521},
+		{"secp192k1", nil, secg(31), Secp192k1},
+		{"secp224k1", nil, secg(32), Secp224k1},
+		{"secp256k1", nil, secg(10), Secp256k1},
+		{"brainpoolP160r1", nil, brainpool(1), BrainpoolP160r1},
+		{"brainpoolP160t1", nil, brainpool(2), BrainpoolP160t1},
+		{"brainpoolP192r1", nil, brainpool(3), BrainpoolP192r1},
+		{"brainpoolP192t1", nil, brainpool(4), BrainpoolP192t1},
+		{"brainpoolP224r1", nil, brainpool(5), BrainpoolP224r1},
+		{"brainpoolP224t1", nil, brainpool(6), BrainpoolP224t1},
+		{"brainpoolP256r1", nil, brainpool(7), BrainpoolP256r1},
+		{"brainpoolP256t1", nil, brainpool(8), BrainpoolP256t1},
+		{"brainpoolP320r1", nil, brainpool(9), BrainpoolP320r1},
+		{"brainpoolP320t1", nil, brainpool(10), BrainpoolP320t1},
+		{"brainpoolP384r1", nil, brainpool(11), BrainpoolP384r1},
+		{"brainpoolP384t1", nil, brainpool(12), BrainpoolP384t1},
+		{"brainpoolP512r1", nil, brainpool(13), BrainpoolP512r1},
+		{"brainpoolP512t1", nil, brainpool(14), BrainpoolP512t1},
+	} {
+		if err := RegisterCurve(info); err != nil {
+			panic(err)
+		}
+	}
+}
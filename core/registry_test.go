@@ -0,0 +1,144 @@
+package core
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCurveByOID uses the DER encoded OIDs from openssl ecparam -name <name> -outform DER.
+func TestCurveByOID(t *testing.T) {
+	for name, der := range map[string]string{
+		"prime192v1":      "06082a8648ce3d030101",
+		"secp224r1":       "06052b81040021",
+		"prime256v1":      "06082a8648ce3d030107",
+		"secp384r1":       "06052b81040022",
+		"secp521r1":       "06052b81040023",
+		"secp192k1":       "06052b8104001f",
+		"secp224k1":       "06052b81040020",
+		"secp256k1":       "06052b8104000a",
+		"brainpoolP160r1": "06092b2403030208010101",
+		"brainpoolP160t1": "06092b2403030208010102",
+		"brainpoolP256r1": "06092b2403030208010107",
+		"brainpoolP512t1": "06092b240303020801010e",
+	} {
+		b, _ := hex.DecodeString(der)
+		var oid asn1.ObjectIdentifier
+		_, err := asn1.Unmarshal(b, &oid)
+		assert.NoError(t, err)
+		byOID, err := CurveByOID(oid)
+		assert.NoError(t, err, name)
+		byName, err := CurveByName(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, byName.Name, byOID.Name)
+	}
+	_, err := CurveByOID(asn1.ObjectIdentifier{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestCurveByName(t *testing.T) {
+	for _, name := range []string{"P-256", "p-256", "secp256r1", "PRIME256V1"} {
+		curve, err := CurveByName(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, "P-256", curve.Name)
+	}
+	_, err := CurveByName("P-257")
+	assert.Error(t, err)
+	// every call returns a new curve
+	c1, _ := CurveByName("secp256k1")
+	c2, _ := CurveByName("secp256k1")
+	assert.False(t, c1 == c2)
+}
+
+// TestCurves checks that every registered curve is constructed under its canonical name and is sane.
+func TestCurves(t *testing.T) {
+	infos := Curves()
+	assert.Len(t, infos, 22)
+	for i, info := range infos {
+		if i > 0 {
+			assert.True(t, infos[i-1].Name < info.Name)
+		}
+		curve := info.New()
+		assert.Equal(t, info.Name, curve.Name)
+		assert.True(t, curve.IsOnCurve(curve.G), info.Name)
+		c, err := CurveByOID(info.OID)
+		assert.NoError(t, err)
+		assert.Equal(t, info.Name, c.Name)
+	}
+}
+
+// TestCurveInfoCopies checks that the registry can't be changed through the CurveInfos going in or out.
+func TestCurveInfoCopies(t *testing.T) {
+	for _, info := range Curves() {
+		if info.Name == "P-256" {
+			info.Name = "P-257"
+			info.Aliases[0] = "secp257r1"
+			info.OID[len(info.OID)-1] = 8
+			info.New = P384
+		}
+	}
+	curve, err := CurveByName("secp256r1")
+	assert.NoError(t, err)
+	assert.Equal(t, "P-256", curve.Name)
+	_, err = CurveByName("secp257r1")
+	assert.Error(t, err)
+	curve, err = CurveByOID(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	assert.NoError(t, err)
+	assert.Equal(t, "P-256", curve.Name)
+	for _, info := range Curves() {
+		if info.Name == "P-256" {
+			assert.Equal(t, []string{"secp256r1", "prime256v1"}, info.Aliases)
+			assert.Equal(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, info.OID)
+		}
+	}
+
+	info := &CurveInfo{Name: "toy3623", Aliases: []string{"hoffstein"}, New: func() *EllipticCurve {
+		curve := newSmallCurve(14, 19, 3623)
+		curve.Name = "toy3623"
+		return curve
+	}}
+	assert.NoError(t, RegisterCurve(info))
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.byName, "toy3623")
+		delete(registry.byName, "hoffstein")
+		registry.infos = registry.infos[:len(registry.infos)-1]
+	})
+	info.Name = "changed"
+	info.Aliases[0] = "changed too"
+	info.New = P256
+	curve, err = CurveByName("hoffstein")
+	assert.NoError(t, err)
+	assert.Equal(t, "toy3623", curve.Name)
+}
+
+func TestRegisterCurve(t *testing.T) {
+	toy := func() *EllipticCurve {
+		curve := newSmallCurve(14, 19, 3623)
+		curve.Name = "toy3623"
+		curve.G, curve.N = pt(6, 730), big.NewInt(3566)
+		return curve
+	}
+	assert.Error(t, RegisterCurve(&CurveInfo{Name: "toy3623"}))
+	assert.Error(t, RegisterCurve(&CurveInfo{Name: "toy3623", Aliases: []string{"p-256"}, New: toy}))
+	assert.Error(t, RegisterCurve(&CurveInfo{Name: "toy3623", OID: asn1.ObjectIdentifier{1, 3, 132, 0, 10}, New: toy}))
+	_, err := CurveByName("toy3623")
+	assert.Error(t, err, "failed registrations leave nothing behind")
+
+	assert.NoError(t, RegisterCurve(&CurveInfo{Name: "toy3623", Aliases: []string{"hoffstein"}, New: toy}))
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.byName, "toy3623")
+		delete(registry.byName, "hoffstein")
+		registry.infos = registry.infos[:len(registry.infos)-1]
+	})
+	curve, err := CurveByName("Hoffstein")
+	assert.NoError(t, err)
+	assert.Equal(t, "toy3623", curve.Name)
+	assert.Error(t, RegisterCurve(&CurveInfo{Name: "toy3623", New: toy}))
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"math/big"
+)
+
+/*
+Scalar is an integer mod N, the order of a curve's base point, i.e. an exponent of the group.
+Scalars are immutable, the arithmetic returns new ones, and both operands of Add or Mul must have
+the same N, mixing scalars of different groups panics.
+
+Unlike []byte scalars, which ScalarMult takes as they are, a Scalar is always reduced to [0, N)
+and encodes to a fixed length, the byte length of N, so encoding doesn't leak its size.
+*/
+type Scalar struct {
+	n *big.Int // the modulus, shared between scalars of the same group
+	v *big.Int // in [0, n)
+}
+
+// NewScalar returns b, a big-endian integer of any length, reduced mod n.
+func NewScalar(n *big.Int, b []byte) *Scalar {
+	v := new(big.Int).SetBytes(b)
+	return &Scalar{n: n, v: v.Mod(v, n)}
+}
+
+/*
+ParseScalar decodes a scalar encoded by Bytes. Unlike NewScalar it doesn't reduce, it returns an
+error if b doesn't have the byte length of n or isn't less than n.
+*/
+func ParseScalar(n *big.Int, b []byte) (*Scalar, error) {
+	if len(b) != (n.BitLen()+7)/8 {
+		return nil, errors.New("core: scalar has the wrong length")
+	}
+	v := new(big.Int).SetBytes(b)
+	if v.Cmp(n) >= 0 {
+		return nil, errors.New("core: scalar is out of range")
+	}
+	return &Scalar{n: n, v: v}, nil
+}
+
+/*
+RandomScalar returns a uniformly random scalar in [1, n-1] by rejection sampling: draw as many bytes
+as n has, clear the bits above the bit length of n, and try again if the result is 0 or at least n.
+Each try succeeds with probability (n-1)/2^bitlen(n), which is about 1/2 at worst, when n is
+just above a power of two, so this ends quickly, and unlike reducing a random number mod n, it's not
+biased towards small values.
+*/
+func RandomScalar(n *big.Int, rand io.Reader) (*Scalar, error) {
+	if n.Cmp(big.NewInt(2)) < 0 {
+		return nil, errors.New("core: order must be at least 2")
+	}
+	bitLen := n.BitLen()
+	b := make([]byte, (bitLen+7)/8)
+	v := new(big.Int)
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		// b is big-endian, the excess bits are at the top of b[0]
+		if excess := uint(len(b)*8 - bitLen); excess > 0 {
+			b[0] &= 0xff >> excess
+		}
+		v.SetBytes(b)
+		if v.Sign() != 0 && v.Cmp(n) < 0 {
+			return &Scalar{n: n, v: v}, nil
+		}
+	}
+}
+
+// Order returns a copy of N.
+func (s *Scalar) Order() *big.Int {
+	return new(big.Int).Set(s.n)
+}
+
+// BigInt returns a copy of the value of s, in [0, N).
+func (s *Scalar) BigInt() *big.Int {
+	return new(big.Int).Set(s.v)
+}
+
+// Bytes returns s big-endian, padded to the byte length of N.
+func (s *Scalar) Bytes() []byte {
+	return s.v.FillBytes(make([]byte, (s.n.BitLen()+7)/8))
+}
+
+// String returns s in decimal.
+func (s *Scalar) String() string {
+	return s.v.String()
+}
+
+// IsZero returns true if s is 0 mod N.
+func (s *Scalar) IsZero() bool {
+	return s.v.Sign() == 0
+}
+
+// Equal returns true if s and t are the same scalar of the same group.
+func (s *Scalar) Equal(t *Scalar) bool {
+	return s.n.Cmp(t.n) == 0 && s.v.Cmp(t.v) == 0
+}
+
+// sameGroup panics if s and t have different N.
+func (s *Scalar) sameGroup(t *Scalar) {
+	if s.n != t.n && s.n.Cmp(t.n) != 0 {
+		panic("core: scalars of different groups")
+	}
+}
+
+// Add returns s+t mod N.
+func (s *Scalar) Add(t *Scalar) *Scalar {
+	s.sameGroup(t)
+	v := new(big.Int).Add(s.v, t.v)
+	if v.Cmp(s.n) >= 0 {
+		v.Sub(v, s.n)
+	}
+	return &Scalar{n: s.n, v: v}
+}
+
+// Sub returns s-t mod N.
+func (s *Scalar) Sub(t *Scalar) *Scalar {
+	return s.Add(t.Negate())
+}
+
+// Mul returns s*t mod N.
+func (s *Scalar) Mul(t *Scalar) *Scalar {
+	s.sameGroup(t)
+	v := new(big.Int).Mul(s.v, t.v)
+	return &Scalar{n: s.n, v: v.Mod(v, s.n)}
+}
+
+// Negate returns -s mod N.
+func (s *Scalar) Negate() *Scalar {
+	v := new(big.Int)
+	if s.v.Sign() != 0 {
+		v.Sub(s.n, s.v)
+	}
+	return &Scalar{n: s.n, v: v}
+}
+
+// Inverse returns 1/s mod N, or an error if there's none, i.e. s is 0 or shares a factor with N.
+func (s *Scalar) Inverse() (*Scalar, error) {
+	v := new(big.Int).ModInverse(s.v, s.n)
+	if v == nil {
+		return nil, errors.New("core: scalar is not invertible")
+	}
+	return &Scalar{n: s.n, v: v}, nil
+}
+
+// checkScalar panics if k is not a scalar of the curve's group.
+func (curve *EllipticCurve) checkScalar(k *Scalar) {
+	if k.n != curve.N && k.n.Cmp(curve.N) != 0 {
+		panic("core: scalar of a different group")
+	}
+}
+
+// Mult returns k*p with the Montgomery ladder, k must be a scalar mod N.
+func (curve *EllipticCurve) Mult(k *Scalar, p *Point) *Point {
+	curve.checkScalar(k)
+	return curve.ScalarMultLadder(k.Bytes(), p)
+}
+
+/*
+BaseMult returns k*G, k must be a scalar mod N. Like Mult it uses the Montgomery ladder, since a
+Scalar is usually a private key or a nonce, so it's slower than ScalarBaseMult, which uses the
+precomputed table of G. Use ScalarBaseMult for public scalars.
+*/
+func (curve *EllipticCurve) BaseMult(k *Scalar) *Point {
+	curve.checkScalar(k)
+	return curve.ScalarMultLadder(k.Bytes(), curve.G)
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalarEncoding(t *testing.T) {
+	curve := P256()
+	s := NewScalar(curve.N, []byte{1})
+	assert.Len(t, s.Bytes(), 32)
+	assert.Equal(t, byte(1), s.Bytes()[31])
+	// NewScalar reduces, N+5 is 5
+	s = NewScalar(curve.N, new(big.Int).Add(curve.N, big.NewInt(5)).Bytes())
+	assert.Equal(t, "5", s.String())
+
+	parsed, err := ParseScalar(curve.N, s.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, parsed.Equal(s))
+	_, err = ParseScalar(curve.N, []byte{5})
+	assert.Error(t, err)
+	_, err = ParseScalar(curve.N, curve.N.Bytes())
+	assert.Error(t, err)
+}
+
+// TestRandomScalarRejection feeds fixed bytes: for N=5 only the low 3 bits are kept,
+// 7 and 0 are rejected and 0xfb = 3 after masking is accepted.
+func TestRandomScalarRejection(t *testing.T) {
+	s, err := RandomScalar(big.NewInt(5), bytes.NewReader([]byte{0x07, 0x00, 0xfb}))
+	assert.NoError(t, err)
+	assert.Equal(t, "3", s.String())
+	_, err = RandomScalar(big.NewInt(5), bytes.NewReader([]byte{0x07}))
+	assert.Error(t, err, "the reader ran out")
+	_, err = RandomScalar(big.NewInt(1), rand.Reader)
+	assert.Error(t, err)
+}
+
+func TestRandomScalarUniform(t *testing.T) {
+	n := big.NewInt(7)
+	counts := make([]int, 7)
+	for i := 0; i < 6000; i++ {
+		s, err := RandomScalar(n, rand.Reader)
+		assert.NoError(t, err)
+		counts[s.BigInt().Int64()]++
+	}
+	assert.Equal(t, 0, counts[0])
+	for v := 1; v < 7; v++ {
+		// 1000 expected, this is more than 6 standard deviations
+		assert.InDelta(t, 1000, counts[v], 200, "value %d", v)
+	}
+}
+
+func TestScalarArithmetic(t *testing.T) {
+	n := P256().N
+	for i := 0; i < 20; i++ {
+		a, _ := RandomScalar(n, rand.Reader)
+		b, _ := RandomScalar(n, rand.Reader)
+		x, y := a.BigInt(), b.BigInt()
+
+		want := new(big.Int).Add(x, y)
+		assert.Equal(t, want.Mod(want, n).String(), a.Add(b).String())
+		want = new(big.Int).Sub(x, y)
+		assert.Equal(t, want.Mod(want, n).String(), a.Sub(b).String())
+		want = new(big.Int).Mul(x, y)
+		assert.Equal(t, want.Mod(want, n).String(), a.Mul(b).String())
+		assert.True(t, a.Add(a.Negate()).IsZero())
+		inv, err := a.Inverse()
+		assert.NoError(t, err)
+		assert.Equal(t, "1", a.Mul(inv).String())
+	}
+	zero := NewScalar(n, nil)
+	assert.True(t, zero.Negate().IsZero())
+	_, err := zero.Inverse()
+	assert.Error(t, err)
+	// 2 has no inverse mod 3566
+	_, err = NewScalar(big.NewInt(3566), []byte{2}).Inverse()
+	assert.Error(t, err)
+}
+
+func TestScalarDifferentGroups(t *testing.T) {
+	a := NewScalar(P256().N, []byte{1})
+	b := NewScalar(Secp256k1().N, []byte{1})
+	assert.Panics(t, func() { a.Add(b) })
+	assert.Panics(t, func() { a.Mul(b) })
+	assert.Panics(t, func() { Secp256k1().BaseMult(a) })
+	// the same N from another instance is fine
+	assert.NotPanics(t, func() { a.Add(NewScalar(P256().N, []byte{2})) })
+}
+
+func TestMult(t *testing.T) {
+	curve := P256()
+	k, _ := RandomScalar(curve.N, rand.Reader)
+	p := curve.ScalarBaseMult([]byte{7})
+	assert.True(t, curve.Equal(curve.Mult(k, p), curve.ScalarMult(k.Bytes(), p)))
+	assert.True(t, curve.Equal(curve.BaseMult(k), curve.ScalarMult(k.Bytes(), curve.G)))
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+/*
+Stats counts the operations done by a curve, so that scalar multiplication algorithms can be
+compared by how much work they do rather than by wall time. Counting is opt-in: set
+EllipticCurve.Stats to a *Stats and every operation on that curve is recorded, leave it nil and
+nothing is counted.
+
+Additions and Doublings count point operations where neither input is the point at infinity,
+since adding to O is just a copy. So nP by double and add costs bitlen(n)-1 doublings and
+one addition less than the number of 1 bits, as in Hoffstein 6.3.1. The exception is
+ScalarMultLadder, which does the full operation on O too, so it counts one addition and one
+doubling for every bit.
+Inversions count field inversions, i.e. ModInverse. FieldMuls and FieldSqrs count field
+multiplications and squarings in the Jacobian arithmetic behind the scalar multiplications,
+the affine Add does its own big.Int arithmetic and only counts towards Additions, Doublings
+and Inversions.
+
+The counters are updated atomically so that they stay correct when the curve is used from
+several goroutines, e.g. by MultiScalarMultPippenger. To measure a single operation, Reset
+before it and Snapshot after it.
+*/
+type Stats struct {
+	Additions  uint64
+	Doublings  uint64
+	Inversions uint64
+	FieldMuls  uint64
+	FieldSqrs  uint64
+}
+
+// Reset sets all counters to zero.
+func (s *Stats) Reset() {
+	atomic.StoreUint64(&s.Additions, 0)
+	atomic.StoreUint64(&s.Doublings, 0)
+	atomic.StoreUint64(&s.Inversions, 0)
+	atomic.StoreUint64(&s.FieldMuls, 0)
+	atomic.StoreUint64(&s.FieldSqrs, 0)
+}
+
+// Snapshot returns a copy of the counters.
+func (s *Stats) Snapshot() Stats {
+	return Stats{
+		Additions:  atomic.LoadUint64(&s.Additions),
+		Doublings:  atomic.LoadUint64(&s.Doublings),
+		Inversions: atomic.LoadUint64(&s.Inversions),
+		FieldMuls:  atomic.LoadUint64(&s.FieldMuls),
+		FieldSqrs:  atomic.LoadUint64(&s.FieldSqrs),
+	}
+}
+
+func (s *Stats) String() string {
+	snap := s.Snapshot()
+	return fmt.Sprintf("%d additions, %d doublings, %d inversions, %d field multiplications, %d field squarings",
+		snap.Additions, snap.Doublings, snap.Inversions, snap.FieldMuls, snap.FieldSqrs)
+}
+
+// op is an operation counted by Stats.
+type op int
+
+const (
+	opAdd op = iota
+	opDouble
+	opInverse
+	opMul
+	opSqr
+)
+
+// count records one operation o if counting is turned on for the curve.
+func (curve *EllipticCurve) count(o op) {
+	s := curve.Stats
+	if s == nil {
+		return
+	}
+	switch o {
+	case opAdd:
+		atomic.AddUint64(&s.Additions, 1)
+	case opDouble:
+		atomic.AddUint64(&s.Doublings, 1)
+	case opInverse:
+		atomic.AddUint64(&s.Inversions, 1)
+	case opMul:
+		atomic.AddUint64(&s.FieldMuls, 1)
+	case opSqr:
+		atomic.AddUint64(&s.FieldSqrs, 1)
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStats checks the counts from the slides: n=2489 costs 11 doublings and 6 additions with
+// double and add, and 11 doublings and 4 additions with the NAF.
+func TestStats(t *testing.T) {
+	curve := newSmallCurve(14, 19, 3623)
+	curve.Stats = &Stats{}
+	p := pt(6, 730)
+	n := big.NewInt(2489).Bytes()
+
+	curve.ScalarMult(n, p)
+	snap := curve.Stats.Snapshot()
+	assert.Equal(t, uint64(6), snap.Additions)
+	assert.Equal(t, uint64(11), snap.Doublings)
+	assert.Equal(t, uint64(1), snap.Inversions)
+
+	curve.Stats.Reset()
+	assert.Equal(t, Stats{}, curve.Stats.Snapshot())
+	curve.ScalarMultNAF(n, p)
+	snap = curve.Stats.Snapshot()
+	assert.Equal(t, uint64(4), snap.Additions)
+	assert.Equal(t, uint64(11), snap.Doublings)
+	assert.Equal(t, uint64(1), snap.Inversions)
+
+	// the affine Add pays an inversion for every addition
+	curve.Stats.Reset()
+	curve.Add(curve.Add(p, p), p)
+	snap = curve.Stats.Snapshot()
+	assert.Equal(t, uint64(1), snap.Additions)
+	assert.Equal(t, uint64(1), snap.Doublings)
+	assert.Equal(t, uint64(2), snap.Inversions)
+	assert.Equal(t, "1 additions, 1 doublings, 2 inversions, 0 field multiplications, 0 field squarings", curve.Stats.String())
+
+	// no counting without Stats
+	curve.Stats = nil
+	curve.ScalarMult(n, p)
+}
+
+// TestStatsDoubling checks the field operation counts of the doubling formulas, A=-3 saves 3 squarings for 1 multiplication.
+func TestStatsDoubling(t *testing.T) {
+	curve := P256()
+	curve.Stats = &Stats{}
+	jp := scaleJacobian(curve, curve.G, 5)
+	curve.Stats.Reset()
+	curve.jacobianDoubleGeneric(jp)
+	assert.Equal(t, Stats{FieldMuls: 2, FieldSqrs: 8}, curve.Stats.Snapshot())
+	curve.Stats.Reset()
+	curve.jacobianDoubleMinus3(jp)
+	assert.Equal(t, Stats{FieldMuls: 3, FieldSqrs: 5}, curve.Stats.Snapshot())
+	curve.Stats.Reset()
+	curve.jacobianDouble(jp)
+	assert.Equal(t, Stats{Doublings: 1, FieldMuls: 3, FieldSqrs: 5}, curve.Stats.Snapshot())
+}
+
+// TestStatsConcurrent counts from several goroutines at once, run with -race.
+func TestStatsConcurrent(t *testing.T) {
+	curve := P256()
+	scalars, points := randomPoints(curve, 40)
+	curve.Stats = &Stats{}
+	curve.MultiScalarMultPippenger(scalars, points, 1)
+	sequential := curve.Stats.Snapshot()
+	curve.Stats.Reset()
+	curve.MultiScalarMultPippenger(scalars, points, 4)
+	assert.Equal(t, sequential, curve.Stats.Snapshot())
+}
+
+// TestStatsLadder checks that the ladder does the same work for every scalar, leading zeros included.
+func TestStatsLadder(t *testing.T) {
+	curve := P256()
+	curve.Stats = &Stats{}
+	nMinus1 := new(big.Int).Sub(curve.N, big.NewInt(1))
+	var counts []Stats
+	for _, k := range []*big.Int{big.NewInt(1), big.NewInt(2), nMinus1, new(big.Int).Rsh(curve.N, 1)} {
+		curve.Stats.Reset()
+		curve.ScalarMultLadder(k.Bytes(), curve.G)
+		counts = append(counts, curve.Stats.Snapshot())
+	}
+	assert.Equal(t, uint64(256), counts[0].Additions)
+	assert.Equal(t, uint64(256), counts[0].Doublings)
+	for _, c := range counts[1:] {
+		assert.Equal(t, counts[0], c)
+	}
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"ecc/field"
+	"errors"
+	"math/big"
+)
+
+/*
+NewEllipticCurve returns the curve y^2 = x^3+ax+b mod p with base point g of order n and cofactor h,
+after checking the parameters with Validate. The parameters are copied, h may be nil if unknown.
+Unlike a struct literal, the result is known to give meaningful arithmetic.
+*/
+func NewEllipticCurve(name string, p, a, b *big.Int, g *Point, n, h *big.Int) (*EllipticCurve, error) {
+	if p == nil || a == nil || b == nil || g == nil || g.X == nil || g.Y == nil || n == nil {
+		return nil, errors.New("core: curve parameters P, A, B, G and N are required")
+	}
+	curve := &EllipticCurve{
+		Name:    name,
+		P:       new(big.Int).Set(p),
+		A:       new(big.Int).Set(a),
+		B:       new(big.Int).Set(b),
+		G:       copyPoint(g),
+		N:       new(big.Int).Set(n),
+		BitSize: p.BitLen(),
+	}
+	if h != nil {
+		curve.H = new(big.Int).Set(h)
+	}
+	if err := curve.Validate(); err != nil {
+		return nil, err
+	}
+	return curve, nil
+}
+
+/*
+Validate checks that the curve parameters make sense and returns an error describing the first
+problem it finds:
+
+- P is an odd prime (so no curves over F_2^m or F_3), small enough for the field package,
+- the curve is not singular, i.e. the discriminant 4A^3+27B^2 != 0 mod p,
+- G is an ordinary point on the curve and N*G is the point at infinity,
+- H*N, the number of points, is within the Hasse bound |#E-(p+1)| <= 2*sqrt(p). If H is nil there
+has to be some cofactor that works.
+
+If N is prime, N*G = O means that N is exactly the order of G. For a composite N, e.g. on a toy
+curve, G might have a smaller order, Validate doesn't factor N to find out.
+Primality is checked with ProbablyPrime, which is exact for numbers below 2^64.
+*/
+func (curve *EllipticCurve) Validate() error {
+	if curve.P == nil || curve.A == nil || curve.B == nil {
+		return errors.New("core: curve parameters P, A and B are required")
+	}
+	P := curve.P
+	if P.Cmp(big.NewInt(3)) <= 0 || !P.ProbablyPrime(20) {
+		return errors.New("core: P must be a prime greater than 3")
+	}
+	if P.BitLen() > 64*field.MaxLimbs {
+		return errors.New("core: P is too large for the field package")
+	}
+	if curve.BitSize != 0 && curve.BitSize != P.BitLen() {
+		return errors.New("core: BitSize doesn't match the size of P")
+	}
+	// 4A^3+27B^2
+	d := new(big.Int).Exp(curve.A, big.NewInt(3), P)
+	d.Mul(d, big.NewInt(4))
+	b2 := new(big.Int).Mul(curve.B, curve.B)
+	d.Add(d, b2.Mul(b2, big.NewInt(27)))
+	if d.Mod(d, P).Sign() == 0 {
+		return errors.New("core: the curve is singular, 4A^3+27B^2 = 0 mod p")
+	}
+
+	if curve.G == nil || curve.G.X == nil || curve.G.Y == nil || curve.N == nil {
+		return errors.New("core: base point G and its order N are required")
+	}
+	if curve.G.Infinity {
+		return errors.New("core: G must not be the point at infinity")
+	}
+	if curve.G.X.Sign() < 0 || curve.G.X.Cmp(P) >= 0 || curve.G.Y.Sign() < 0 || curve.G.Y.Cmp(P) >= 0 {
+		return errors.New("core: coordinates of G are out of range")
+	}
+	if !curve.IsOnCurve(curve.G) {
+		return errors.New("core: G is not on the curve")
+	}
+	if curve.N.Cmp(big.NewInt(2)) < 0 {
+		return errors.New("core: N must be at least 2")
+	}
+	if !curve.ScalarMult(curve.N.Bytes(), curve.G).Infinity {
+		return errors.New("core: N*G is not the point at infinity, N is not the order of G")
+	}
+
+	if curve.H != nil {
+		if curve.H.Sign() <= 0 {
+			return errors.New("core: the cofactor H must be positive")
+		}
+		if !hasse(P, new(big.Int).Mul(curve.H, curve.N)) {
+			return errors.New("core: H*N is outside the Hasse bound, H or N is wrong")
+		}
+	} else if !hasseCofactor(P, curve.N) {
+		return errors.New("core: no multiple of N is within the Hasse bound, N is wrong")
+	}
+	return nil
+}
+
+// hasse returns true if |n-(p+1)| <= 2*sqrt(p), i.e. (n-(p+1))^2 <= 4p.
+func hasse(p, n *big.Int) bool {
+	t := new(big.Int).Add(p, big.NewInt(1))
+	t.Sub(n, t)
+	t.Mul(t, t)
+	return t.Cmp(new(big.Int).Lsh(p, 2)) <= 0
+}
+
+/*
+hasseCofactor returns true if some multiple hN, h >= 1, is within the Hasse bound. The smallest
+candidate is the first multiple of N above p+1-2*sqrt(p), i.e. ceil(lo/N)*N. lo is rounded down
+by less than 2, so the candidate can fall just short of the bound, then the next one is checked.
+*/
+func hasseCofactor(p, n *big.Int) bool {
+	// lo = p+1-2*sqrt(p), rounded down so that we don't skip a valid multiple
+	lo := new(big.Int).Sqrt(new(big.Int).Lsh(p, 2))
+	lo.Add(lo, big.NewInt(1))
+	lo.Sub(new(big.Int).Add(p, big.NewInt(1)), lo)
+	h := new(big.Int).Add(lo, new(big.Int).Sub(n, big.NewInt(1)))
+	h.Div(h, n)
+	if h.Sign() <= 0 {
+		h.SetInt64(1)
+	}
+	hn := new(big.Int).Mul(h, n)
+	return hasse(p, hn) || hasse(p, hn.Add(hn, n))
+}
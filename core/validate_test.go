@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRegistered(t *testing.T) {
+	for _, info := range Curves() {
+		assert.NoError(t, info.New().Validate(), info.Name)
+	}
+}
+
+func TestNewEllipticCurve(t *testing.T) {
+	// (6,730) generates all 3566 points, 2*(6,730) = (2521,3601) has order 1783 with cofactor 2
+	curve, err := NewEllipticCurve("toy", big.NewInt(3623), big.NewInt(14), big.NewInt(19), pt(6, 730), big.NewInt(3566), big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 12, curve.BitSize)
+	_, err = NewEllipticCurve("toy", big.NewInt(3623), big.NewInt(14), big.NewInt(19), pt(2521, 3601), big.NewInt(1783), big.NewInt(2))
+	assert.NoError(t, err)
+	_, err = NewEllipticCurve("toy", big.NewInt(3623), big.NewInt(14), big.NewInt(19), pt(2521, 3601), big.NewInt(1783), nil)
+	assert.NoError(t, err)
+
+	// the parameters are copied
+	p := big.NewInt(3623)
+	curve, _ = NewEllipticCurve("toy", p, big.NewInt(14), big.NewInt(19), pt(6, 730), big.NewInt(3566), nil)
+	p.SetInt64(5)
+	assert.Equal(t, int64(3623), curve.P.Int64())
+
+	_, err = NewEllipticCurve("toy", big.NewInt(3623), big.NewInt(14), big.NewInt(19), nil, big.NewInt(3566), nil)
+	assert.Error(t, err)
+}
+
+func TestValidateErrors(t *testing.T) {
+	for name, c := range map[string]struct {
+		p, a, b int64
+		g       *Point
+		n, h    int64  // h = 0 means nil
+		err     string // part of the error message
+	}{
+		"P composite":         {3621, 14, 19, pt(6, 730), 3566, 1, "prime"},
+		"P = 3":               {3, 1, 1, pt(0, 1), 4, 1, "prime"},
+		"singular":            {3623, 0, 0, pt(1, 1), 3566, 0, "singular"},
+		"singular, nonzero A": {13, -3, 2, pt(2, 4), 12, 0, "singular"}, // 4(-27)+27*4 = 0
+		"G not on curve":      {3623, 14, 19, pt(6, 731), 3566, 1, "not on the curve"},
+		"G out of range":      {3623, 14, 19, pt(6+3623, 730), 3566, 1, "out of range"},
+		"N*G != O":            {3623, 14, 19, pt(6, 730), 1783, 2, "N*G"},
+		"N = 1":               {3623, 14, 19, pt(6, 730), 1, 3566, "at least 2"},
+		"wrong cofactor":      {3623, 14, 19, pt(2521, 3601), 1783, 3, "Hasse"},
+		"negative cofactor":   {3623, 14, 19, pt(2521, 3601), 1783, -2, "positive"},
+		"N too large":         {3623, 14, 19, pt(6, 730), 2 * 3566, 0, "Hasse"},
+	} {
+		curve := newSmallCurve(c.a, c.b, c.p)
+		curve.G, curve.N = c.g, big.NewInt(c.n)
+		if c.h != 0 {
+			curve.H = big.NewInt(c.h)
+		}
+		err := curve.Validate()
+		if assert.Error(t, err, name) {
+			assert.Contains(t, err.Error(), c.err, name)
+		}
+	}
+
+	curve := P256()
+	curve.BitSize = 255
+	assert.Error(t, curve.Validate())
+	curve = smallCurves[1]
+	assert.Error(t, curve.Validate(), "no G and N")
+	curve = &EllipticCurve{P: new(big.Int).Lsh(big.NewInt(1), 600), A: big.NewInt(1), B: big.NewInt(1)}
+	curve.P.Add(curve.P, big.NewInt(1))
+	for !curve.P.ProbablyPrime(20) {
+		curve.P.Add(curve.P, big.NewInt(2))
+	}
+	assert.Error(t, curve.Validate(), "P too large")
+}
+
+func TestHasse(t *testing.T) {
+	// p = 3623: p+1 = 3624, 2*sqrt(p) = 120.4, so #E is in [3504, 3744]
+	p := big.NewInt(3623)
+	assert.True(t, hasse(p, big.NewInt(3504)))
+	assert.True(t, hasse(p, big.NewInt(3744)))
+	assert.False(t, hasse(p, big.NewInt(3503)))
+	assert.False(t, hasse(p, big.NewInt(3745)))
+	assert.True(t, hasseCofactor(p, big.NewInt(1783)))
+	assert.True(t, hasseCofactor(p, big.NewInt(2)))
+	assert.True(t, hasseCofactor(p, big.NewInt(3744)))
+	assert.False(t, hasseCofactor(p, big.NewInt(3745)))
+	assert.False(t, hasseCofactor(p, big.NewInt(1900))) // 1900 < 3504 < 3800
+}
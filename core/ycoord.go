@@ -0,0 +1,60 @@
+package core
+
+import (
+	"ecc/field"
+	"errors"
+	"math/big"
+)
+
+/*
+YFromX returns the two y-coordinates of the points on the curve with x-coordinate x, i.e. the
+square roots of x^3+Ax+B mod p. y1 is the even one and y2 = p-y1 the odd one, so the points are
+(x, y1) and (x, y2) = -(x, y1). If y1 = 0 both are 0, (x, 0) is a point of order 2.
+It returns an error if x is not in [0, p) or x^3+Ax+B is not a square, i.e. no point has this x.
+The square root is field.Sqrt, which uses Tonelli-Shanks with faster paths for p = 3 mod 4
+(all NIST primes except P-224, and secp256k1) and p = 5 mod 8.
+*/
+func (curve *EllipticCurve) YFromX(x *big.Int) (y1, y2 *big.Int, err error) {
+	if x.Sign() < 0 || x.Cmp(curve.P) >= 0 {
+		return nil, nil, errors.New("core: x is out of range")
+	}
+	fp := curve.primeField()
+	// rhs = x^3+Ax+B
+	var ex, rhs, t, y field.Element
+	fp.SetBigInt(&ex, x)
+	fp.Square(&rhs, &ex)
+	fp.Add(&rhs, &rhs, &curve.a)
+	fp.Mul(&rhs, &rhs, &ex)
+	fp.SetBigInt(&t, curve.B)
+	fp.Add(&rhs, &rhs, &t)
+	if fp.Sqrt(&y, &rhs) == nil {
+		return nil, nil, errors.New("core: x is not the x-coordinate of a point on the curve")
+	}
+	y1 = fp.BigInt(&y)
+	if y1.Bit(0) == 1 {
+		y1.Sub(curve.P, y1)
+	}
+	y2 = new(big.Int).Sub(curve.P, y1)
+	y2.Mod(y2, curve.P)
+	return y1, y2, nil
+}
+
+/*
+PointFromX returns the point with x-coordinate x whose y-coordinate is odd if odd is true and
+even otherwise, which is how compressed points pick their y. See YFromX for the errors.
+For a point of order 2 y = 0 is even, asking for the odd one is an error.
+*/
+func (curve *EllipticCurve) PointFromX(x *big.Int, odd bool) (*Point, error) {
+	y1, y2, err := curve.YFromX(x)
+	if err != nil {
+		return nil, err
+	}
+	y := y1
+	if odd {
+		if len(y1.Bits()) == 0 {
+			return nil, errors.New("core: point of order 2 has no odd y-coordinate")
+		}
+		y = y2
+	}
+	return &Point{X: new(big.Int).Set(x), Y: y}, nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestYFromXSmallCurves checks every x against a table of square roots.
+// p = 97 is 1 mod 8, so it goes through Tonelli-Shanks.
+func TestYFromXSmallCurves(t *testing.T) {
+	curves := append([]*EllipticCurve{newSmallCurve(2, 3, 97)}, smallCurves...)
+	for _, curve := range curves {
+		p := curve.P.Int64()
+		roots := map[int64][]int64{}
+		for y := int64(0); y < p; y++ {
+			roots[y*y%p] = append(roots[y*y%p], y)
+		}
+		for x := int64(0); x < p; x++ {
+			rhs := ((x*x*x+curve.A.Int64()*x+curve.B.Int64())%p + p) % p
+			want, ok := roots[rhs]
+			y1, y2, err := curve.YFromX(big.NewInt(x))
+			if !ok {
+				assert.Error(t, err, "%s x=%d", curve.Name, x)
+				continue
+			}
+			if !assert.NoError(t, err, "%s x=%d", curve.Name, x) {
+				continue
+			}
+			assert.Equal(t, uint(0), y1.Bit(0), "%s x=%d", curve.Name, x)
+			assert.True(t, curve.IsOnCurve(&Point{X: big.NewInt(x), Y: y1}))
+			assert.True(t, curve.IsOnCurve(&Point{X: big.NewInt(x), Y: y2}))
+			if len(want) == 1 { // 2-torsion
+				assert.Equal(t, int64(0), y1.Int64())
+				assert.Equal(t, int64(0), y2.Int64())
+			} else {
+				assert.ElementsMatch(t, want, []int64{y1.Int64(), y2.Int64()}, "%s x=%d", curve.Name, x)
+			}
+		}
+	}
+}
+
+func TestYFromXOutOfRange(t *testing.T) {
+	curve := smallCurves[1]
+	_, _, err := curve.YFromX(big.NewInt(-1))
+	assert.Error(t, err)
+	_, _, err = curve.YFromX(big.NewInt(3623 + 6))
+	assert.Error(t, err)
+}
+
+func TestPointFromX(t *testing.T) {
+	curve := smallCurves[1]
+	p, err := curve.PointFromX(big.NewInt(6), false)
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(p, pt(6, 730)))
+	p, err = curve.PointFromX(big.NewInt(6), true)
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(p, pt(6, 3623-730)))
+	// (802, 0) has order 2
+	p, err = curve.PointFromX(big.NewInt(802), false)
+	assert.NoError(t, err)
+	assert.True(t, curve.Equal(p, pt(802, 0)))
+	_, err = curve.PointFromX(big.NewInt(802), true)
+	assert.Error(t, err)
+}
+
+func TestPointFromXLarge(t *testing.T) {
+	p256 := elliptic.P256()
+	curve := P256()
+	for i := 0; i < 20; i++ {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		x, y := p256.ScalarBaseMult(b)
+		p, err := curve.PointFromX(x, y.Bit(0) == 1)
+		assert.NoError(t, err)
+		assert.True(t, curve.Equal(p, &Point{X: x, Y: y}))
+	}
+	// about half of the x aren't on the curve
+	for i := 0; i < 20; i++ {
+		x, _ := rand.Int(rand.Reader, curve.P)
+		rhs := new(big.Int).Exp(x, big.NewInt(3), curve.P)
+		rhs.Add(rhs, new(big.Int).Mul(curve.A, x))
+		rhs.Add(rhs, curve.B)
+		rhs.Mod(rhs, curve.P)
+		_, _, err := curve.YFromX(x)
+		assert.Equal(t, new(big.Int).ModSqrt(rhs, curve.P) == nil, err != nil)
+	}
+}
+
+func BenchmarkYFromX(b *testing.B) {
+	curve := P256()
+	x := curve.G.X
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = curve.YFromX(x)
+	}
+}
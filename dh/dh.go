@@ -1,9 +1,11 @@
 package dh
 
 import (
-	"crypto/elliptic"
+	"crypto/rand"
 	"ecc/core"
+	"errors"
 	"fmt"
+	"math/big"
 )
 
 /*
@@ -15,70 +17,146 @@ that everything would work.
 */
 func DHExample() {
 	fmt.Println("------ Diffie-Hellman ------")
-	curve := elliptic.P256()
+	// any core.Group works, e.g. a NIST curve and an Edwards curve
+	for _, group := range []core.Group{core.P256(), core.Ed25519()} {
+		ret, err := dh(group)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("DH works?", ret)
+	}
+}
 
+// dh simulates the exchange between A and B in group, see DHExample.
+func dh(group core.Group) (bool, error) {
 	// A generates her private/public key pair
-	pairA, err := core.GenerateKey(curve)
+	pairA, err := core.GenerateKey(group, rand.Reader)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return false, err
 	}
 	// B generates his private/public key pair
-	pairB, err := core.GenerateKey(curve)
+	pairB, err := core.GenerateKey(group, rand.Reader)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return false, err
 	}
 
 	// Transmit step: A sends pairA.Pub and B sends pairB.Pub to each other.
 
-	// A calculates her shared key
-	sharedA := &core.Point{nil, nil}
-	sharedA.X, sharedA.Y = curve.ScalarMult(pairB.Pub.X, pairB.Pub.Y, pairA.Priv)
+	// Each checks the key it received, a point of small order would leak the private key mod its order
+	for _, pub := range []*core.Point{pairA.Pub, pairB.Pub} {
+		if err := group.ValidatePoint(pub); err != nil {
+			return false, err
+		}
+		if group.Equal(pub, group.Identity()) {
+			return false, errors.New("dh: public key is the identity")
+		}
+	}
+
+	// A calculates her shared key, the private keys are secret so use ScalarMultSecret
+	sharedA := group.ScalarMultSecret(pairA.Priv.Bytes(), pairB.Pub)
 	// B calculates his shared key
-	sharedB := &core.Point{nil, nil}
-	sharedB.X, sharedB.Y = curve.ScalarMult(pairA.Pub.X, pairA.Pub.Y, pairB.Priv)
+	sharedB := group.ScalarMultSecret(pairB.Priv.Bytes(), pairA.Pub)
 	// verify that the shared keys are equal
-	ret := (sharedA.X.Cmp(sharedB.X) == 0) && (sharedA.Y.Cmp(sharedB.Y) == 0)
-	fmt.Println("DH works?", ret)
+	return group.Equal(sharedA, sharedB), nil
 }
 
 /*
-shortDH verifies that a shorter implementation of DH works, similar to dh func.
+In x-only DH, also called short DH, A and B only transmit the x-coordinate of their public key.
+Each party lifts the other's x back to a point with a square root, see core.PointFromX, and the
+shared key is the x-coordinate of the shared point.
 
-In shortDH, A and B only transmits the x-coord of their public key, each party
-then calculate the y-coord by taking square root mod ?. The shared key is the
-x-coord of the shared point, which is well-defined.
+It doesn't matter which of the two square roots they pick: the points with a given x are Q and -Q,
+and k(-Q) = -(kQ) has the same x as kQ. So the sign of y never has to be transmitted.
 
 In practice, this is probably rarely used since it makes sense for A and B to
 publish their public keys.
-
-TODO: abandon this because golang's ECC lib doesn't have an easy way to
-calculate y-coord of a point given x-coord, lacking a straight forward
-way to access the curve.
 */
+
+// ShortDHPublic returns the x-coordinate of priv*G, which is all that's transmitted.
+func ShortDHPublic(curve *core.EllipticCurve, priv []byte) (*big.Int, error) {
+	pub := curve.ScalarMultSecret(priv, curve.G)
+	if pub.Infinity {
+		return nil, errors.New("dh: private key is a multiple of the order of G")
+	}
+	return pub.X, nil
+}
+
 /*
-func shortDH(curve elliptic.Curve) (bool, error) {
-	// A generates her private/public key pair
-	pairA, err := generateKey(curve)
+ShortDHShared lifts peerX to a point on the curve and returns the x-coordinate of priv times it.
+It returns an error if peerX is not the x-coordinate of a point on the curve, if that point is not
+in the subgroup generated by G, or if the shared point is the point at infinity.
+The multiplication is SafeScalarMult, i.e. the Montgomery ladder since priv is secret.
+*/
+func ShortDHShared(curve *core.EllipticCurve, priv []byte, peerX *big.Int) (*big.Int, error) {
+	peer, err := curve.PointFromX(peerX, false)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := curve.SafeScalarMult(priv, peer)
+	if err != nil {
+		return nil, err
+	}
+	if shared.Infinity {
+		return nil, errors.New("dh: shared point is the point at infinity")
+	}
+	return shared.X, nil
+}
+
+// ShortDHExample runs shortDH on P-256, like DHExample.
+func ShortDHExample() {
+	fmt.Println("------ short Diffie-Hellman ------")
+	ok, err := shortDH(core.P256())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("short DH works?", ok)
+}
+
+// shortDH simulates the exchange between A and B with x-only public keys.
+func shortDH(curve *core.EllipticCurve) (bool, error) {
+	// A generates her private key and x-only public key
+	privA, err := randomPriv(curve)
 	if err != nil {
 		return false, err
 	}
-	// B generates his private/public key pair
-	pairB, err := generateKey(curve)
+	pubA, err := ShortDHPublic(curve, privA)
+	if err != nil {
+		return false, err
+	}
+	// B generates his private key and x-only public key
+	privB, err := randomPriv(curve)
+	if err != nil {
+		return false, err
+	}
+	pubB, err := ShortDHPublic(curve, privB)
 	if err != nil {
 		return false, err
 	}
 
-	// Transmit step: A transmits pairA.Pub.X and B transmits pairB.Pub.X
+	// Transmit step: A transmits pubA and B transmits pubB
 
 	// A calculates her shared key
-	// first calculate B's public key y-coord
-	// then calculate shared key just as standard DH, the shared key is x-coord
-
-
+	sharedA, err := ShortDHShared(curve, privA, pubB)
+	if err != nil {
+		return false, err
+	}
 	// B calculates his shared key
+	sharedB, err := ShortDHShared(curve, privB, pubA)
+	if err != nil {
+		return false, err
+	}
 
 	// verify that the shared keys matched
+	return sharedA.Cmp(sharedB) == 0, nil
+}
+
+// randomPriv returns a random private key in [1, N-1].
+func randomPriv(group core.Group) ([]byte, error) {
+	k, err := core.RandomScalar(group.Order(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return k.Bytes(), nil
 }
-*/
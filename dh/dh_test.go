@@ -0,0 +1,90 @@
+package dh
+
+import (
+	"ecc/core"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+toyCurve is y^2=x^3+14x+19 mod 3623 from Hoffstein. It has 3566 = 2*1783 points, so G is
+(2521,3601) = 2*(6,730), which generates the subgroup of prime order 1783. With the whole group a
+private key could share the factor 2 with the order and the shared point could be O.
+*/
+func toyCurve() *core.EllipticCurve {
+	return &core.EllipticCurve{
+		Name: "y^2=x^3+14x+19 mod 3623",
+		P:    big.NewInt(3623),
+		N:    big.NewInt(1783),
+		H:    big.NewInt(2),
+		A:    big.NewInt(14),
+		B:    big.NewInt(19),
+		G:    &core.Point{X: big.NewInt(2521), Y: big.NewInt(3601)},
+	}
+}
+
+// toyCurve97 is y^2=x^3+2x+3 mod 97, p = 1 mod 8 so lifting x goes through Tonelli-Shanks.
+// (3,6) has order 5.
+func toyCurve97() *core.EllipticCurve {
+	return &core.EllipticCurve{
+		Name: "y^2=x^3+2x+3 mod 97",
+		P:    big.NewInt(97),
+		N:    big.NewInt(5),
+		A:    big.NewInt(2),
+		B:    big.NewInt(3),
+		G:    &core.Point{X: big.NewInt(3), Y: big.NewInt(6)},
+	}
+}
+
+func TestShortDH(t *testing.T) {
+	for _, curve := range []*core.EllipticCurve{core.P256(), toyCurve(), toyCurve97()} {
+		for i := 0; i < 20; i++ {
+			ok, err := shortDH(curve)
+			assert.NoError(t, err, curve.Name)
+			assert.True(t, ok, curve.Name)
+		}
+	}
+}
+
+// TestShortDHMatchesDH checks that the shared x is the x of the full DH shared point.
+func TestShortDHMatchesDH(t *testing.T) {
+	for _, curve := range []*core.EllipticCurve{core.P256(), toyCurve()} {
+		privA, err := randomPriv(curve)
+		assert.NoError(t, err)
+		privB, err := randomPriv(curve)
+		assert.NoError(t, err)
+		pubB, err := ShortDHPublic(curve, privB)
+		assert.NoError(t, err)
+		shared, err := ShortDHShared(curve, privA, pubB)
+		assert.NoError(t, err)
+		full := curve.ScalarMult(privA, curve.ScalarBaseMult(privB))
+		assert.Equal(t, full.X.String(), shared.String(), curve.Name)
+	}
+}
+
+func TestShortDHErrors(t *testing.T) {
+	curve := toyCurve()
+	// x = 3 isn't on the curve: 27+42+19 = 88 is not a square mod 3623
+	_, err := ShortDHShared(curve, []byte{5}, big.NewInt(3))
+	assert.Error(t, err)
+	// (802,0) has order 2, it's on the curve but not in the subgroup
+	_, err = ShortDHShared(curve, []byte{6}, big.NewInt(802))
+	assert.Error(t, err)
+	_, err = ShortDHPublic(curve, big.NewInt(1783).Bytes())
+	assert.Error(t, err)
+	// the mod 97 curve has 100 points, (0,10) is on it but not in the subgroup of order 5
+	_, err = ShortDHShared(toyCurve97(), []byte{3}, big.NewInt(0))
+	assert.Error(t, err)
+}
+
+func TestDH(t *testing.T) {
+	for _, group := range []core.Group{core.P256(), core.Secp256k1(), toyCurve(), toyCurve97(), core.Ed25519()} {
+		for i := 0; i < 10; i++ {
+			ok, err := dh(group)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}
+	}
+}
@@ -1,28 +1,35 @@
 package ds
 
 import (
-	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"ecc/core"
 	"fmt"
 	"math/big"
 )
 
 /*
-This is purposely done very naively using golang elliptic lib, without the
-rest of this lib.
+This is purposely done very naively, the only parts of this lib that are used
+are the group operations and DoubleScalarMult for verification.
+It runs on any core.Group, here P-256 and Ed25519.
 */
 func SchnorrExample() {
+	for _, group := range []core.Group{core.P256(), core.Ed25519()} {
+		schnorrExample(group)
+	}
+}
+
+func schnorrExample(group core.Group) {
 	fmt.Println("------ Schnoor example ------")
 	// key generation
-	curve := elliptic.P256()
-	priv, x, y, err := elliptic.GenerateKey(curve, rand.Reader)
+	pair, err := core.GenerateKey(group, rand.Reader)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	priv, pub := pair.Priv.Bytes(), pair.Pub
 	fmt.Println("private key size", len(priv), priv)
-	fmt.Println("public key", x, y)
+	fmt.Println("public key", pub.X, pub.Y)
 
 	// message
 	msg := "hello, world"
@@ -30,7 +37,7 @@ func SchnorrExample() {
 	fmt.Printf("message digest type %T, digest %v\n", digest, digest)
 
 	// signing
-	s, e, err := sign(curve, priv, digest[:])
+	s, e, err := sign(group, priv, digest[:])
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -38,27 +45,27 @@ func SchnorrExample() {
 	fmt.Println("signature", s, e)
 
 	// verifying
-	fmt.Println("Schnoor works?", verify(curve, x, y, s, e, digest[:]))
+	fmt.Println("Schnoor works?", verify(group, pub, s, e, digest[:]))
 }
 
-func sign(curve elliptic.Curve, priv, digest []byte) (s, e *big.Int, err error) {
-	N := curve.Params().N
+/*
+sign returns the signature (s, e) where e = H(r || digest) with r = kG the encoded commitment,
+and s = k - priv*e mod N. The hash covers the encoding of r rather than its x-coordinate so that
+it works the same for every group.
+*/
+func sign(group core.Group, priv, digest []byte) (s, e *big.Int, err error) {
+	N := group.Order()
 	// k is a random elt of F_N, this is purposely not following NSA A.2.1
-	b := make([]byte, curve.Params().BitSize/8)
-	_, err = rand.Read(b)
+	k, err := rand.Int(rand.Reader, N)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, nil, err
 	}
-	k := new(big.Int).SetBytes(b)
-	k.Mod(k, N)
-	fmt.Println("k", k.BitLen(), k)
 
-	r_x, _ := curve.ScalarBaseMult(k.Bytes())
-	fmt.Println("x(r)", r_x.BitLen(), r_x)
+	// k is as secret as priv, anyone who learns it can solve s = k - priv*e for priv
+	r := group.MarshalPoint(group.ScalarMultSecret(k.Bytes(), group.Generator()))
 
 	md := sha256.New()
-	md.Write(r_x.Bytes())
+	md.Write(r)
 	md.Write(digest[:])
 	e = new(big.Int).SetBytes(md.Sum(nil))
 
@@ -69,16 +76,20 @@ func sign(curve elliptic.Curve, priv, digest []byte) (s, e *big.Int, err error)
 	return s, e, nil
 }
 
-func verify(curve elliptic.Curve, x, y, s, e *big.Int, digest []byte) bool {
-	tmp1_x, tmp1_y := curve.ScalarMult(x, y, e.Bytes())
-	tmp2_x, tmp2_y := curve.ScalarBaseMult(s.Bytes())
-	rv_x, _ := curve.Add(tmp1_x, tmp1_y, tmp2_x, tmp2_y)
-	fmt.Println("rv_x", rv_x)
+/*
+verify recomputes r = eX + sG = e*priv*G + (k - priv*e)*G = kG, with Shamir's trick
+instead of two scalar multiplications.
+*/
+func verify(group core.Group, pub *core.Point, s, e *big.Int, digest []byte) bool {
+	// pub comes from outside, a small order key would make forgeries easy
+	if group.ValidatePoint(pub) != nil || group.Equal(pub, group.Identity()) {
+		return false
+	}
+	rv := group.MarshalPoint(group.DoubleScalarMult(e.Bytes(), pub, s.Bytes(), group.Generator()))
 
 	md := sha256.New()
-	md.Write(rv_x.Bytes())
+	md.Write(rv)
 	md.Write(digest)
 	e_v := new(big.Int).SetBytes(md.Sum(nil))
-	fmt.Println("e_v", e_v)
 	return e_v.Cmp(e) == 0
 }
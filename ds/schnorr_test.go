@@ -0,0 +1,66 @@
+package ds
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"ecc/core"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testGroups are a NIST curve, a Koblitz curve, a toy curve where (6,730) generates all 3566 points, and Ed25519.
+func testGroups() []core.Group {
+	toy := &core.EllipticCurve{
+		Name: "y^2=x^3+14x+19 mod 3623",
+		P:    big.NewInt(3623),
+		N:    big.NewInt(3566),
+		A:    big.NewInt(14),
+		B:    big.NewInt(19),
+		G:    &core.Point{X: big.NewInt(6), Y: big.NewInt(730)},
+	}
+	return []core.Group{core.P256(), core.Secp256k1(), toy, core.Ed25519()}
+}
+
+func TestSchnorr(t *testing.T) {
+	for _, group := range testGroups() {
+		pair, err := core.GenerateKey(group, rand.Reader)
+		assert.NoError(t, err)
+		priv, pub := pair.Priv.Bytes(), pair.Pub
+		digest := sha256.Sum256([]byte("hello, world"))
+		s, e, err := sign(group, priv, digest[:])
+		assert.NoError(t, err)
+		assert.True(t, verify(group, pub, s, e, digest[:]))
+
+		other := sha256.Sum256([]byte("hello, world!"))
+		assert.False(t, verify(group, pub, s, e, other[:]))
+		// on the toy curve a wrong key still verifies with probability 1/N
+		if group.Order().BitLen() > 64 {
+			assert.False(t, verify(group, group.Add(pub, group.Generator()), s, e, digest[:]))
+		}
+	}
+}
+
+/*
+TestVerifySmallOrderKey forges a signature for a public key of order 2 on Ed25519: with R = sG,
+eX+sG = R whenever e is even, so every other (s, e) would verify without the check on pub.
+*/
+func TestVerifySmallOrderKey(t *testing.T) {
+	group := core.Ed25519()
+	pub := &core.Point{X: big.NewInt(0), Y: new(big.Int).Sub(group.P, big.NewInt(1))}
+	digest := sha256.Sum256([]byte("hello, world"))
+	for i := int64(1); ; i++ {
+		s := big.NewInt(i)
+		md := sha256.New()
+		md.Write(group.MarshalPoint(group.ScalarBaseMult(s.Bytes())))
+		md.Write(digest[:])
+		e := new(big.Int).SetBytes(md.Sum(nil))
+		if e.Bit(0) == 0 {
+			assert.True(t, group.Equal(group.DoubleScalarMult(e.Bytes(), pub, s.Bytes(), group.Generator()), group.ScalarBaseMult(s.Bytes())))
+			assert.False(t, verify(group, pub, s, e, digest[:]))
+			break
+		}
+	}
+	assert.False(t, verify(group, group.Identity(), big.NewInt(1), big.NewInt(2), digest[:]))
+}
@@ -0,0 +1,186 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"ecc/core"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+/*
+ECDSA as in SEC1 4.1 and FIPS 186-4, on any core.EllipticCurve. crypto/ecdsa does the same but only
+on curves with A = -3, here the curve can be any short Weierstrass curve, e.g. secp256k1 or the
+Brainpool curves. On P256() the signatures are interchangeable with crypto/ecdsa.
+
+Like the rest of this lib, it's for learning, the arithmetic is not constant time.
+*/
+
+// PublicKey is a point Q = dG on Curve.
+type PublicKey struct {
+	Curve *core.EllipticCurve
+	Q     *core.Point
+}
+
+// PrivateKey is the secret scalar D with its public key.
+type PrivateKey struct {
+	PublicKey
+	D *core.Scalar
+}
+
+// GenerateKey returns a new key pair on curve, see core.GenerateKey.
+func GenerateKey(curve *core.EllipticCurve, rand io.Reader) (*PrivateKey, error) {
+	pair, err := core.GenerateKey(curve, rand)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{PublicKey: PublicKey{Curve: curve, Q: pair.Pub}, D: pair.Priv}, nil
+}
+
+/*
+hashToScalar converts the hash of the message to an integer as in SEC1 4.1.3 step 5: if the hash is
+longer than N, only its leftmost bitlen(N) bits are kept, then it's reduced mod N.
+*/
+func hashToScalar(N *big.Int, hash []byte) *core.Scalar {
+	bitLen := N.BitLen()
+	if len(hash)*8 > bitLen {
+		hash = hash[:(bitLen+7)/8]
+	}
+	e := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - bitLen; excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return core.NewScalar(N, e.Bytes())
+}
+
+/*
+Sign signs hash, the digest of the message, with priv and returns the signature (r, s):
+pick a random nonce k in [1, N-1], r = x(kG) mod N and s = (e + r*d)/k mod N, with e the hash as
+a scalar. A nonce that gives r = 0 or s = 0 is thrown away and we try again.
+
+The nonce must never repeat or leak, two signatures with the same k give away d. kG uses the
+Montgomery ladder since k is secret.
+*/
+func Sign(rand io.Reader, priv *PrivateKey, hash []byte) (r, s *big.Int, err error) {
+	curve := priv.Curve
+	e := hashToScalar(curve.N, hash)
+	for {
+		k, err := core.RandomScalar(curve.N, rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		R := curve.Mult(k, curve.G)
+		if R.Infinity {
+			continue
+		}
+		rs := core.NewScalar(curve.N, R.X.Bytes())
+		if rs.IsZero() {
+			continue
+		}
+		kInv, err := k.Inverse()
+		if err != nil {
+			// N is not prime
+			return nil, nil, err
+		}
+		ss := kInv.Mul(e.Add(rs.Mul(priv.D)))
+		if ss.IsZero() {
+			continue
+		}
+		return rs.BigInt(), ss.BigInt(), nil
+	}
+}
+
+/*
+SignLowS is Sign but returns the low-S form of the signature, see IsLowS. Verify accepts both,
+VerifyLowS only the low-S form.
+*/
+func SignLowS(rand io.Reader, priv *PrivateKey, hash []byte) (r, s *big.Int, err error) {
+	r, s, err = Sign(rand, priv, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, NormalizeS(priv.Curve, s), nil
+}
+
+/*
+If (r, s) is a valid signature then so is (r, N-s): -k gives the same x-coordinate as k and
+flips the sign of s. Anyone can make the second signature from the first without the key, which is
+a problem when signatures identify something, like transactions in Bitcoin. The fix is to only
+allow the smaller of s and N-s, that's the low-S form.
+*/
+
+// IsLowS returns true if s <= N/2.
+func IsLowS(curve *core.EllipticCurve, s *big.Int) bool {
+	half := new(big.Int).Rsh(curve.N, 1)
+	return s.Cmp(half) <= 0
+}
+
+// NormalizeS returns the low-S form of s, i.e. s or N-s, whichever is not greater than N/2.
+func NormalizeS(curve *core.EllipticCurve, s *big.Int) *big.Int {
+	if IsLowS(curve, s) {
+		return new(big.Int).Set(s)
+	}
+	return new(big.Int).Sub(curve.N, s)
+}
+
+/*
+Verify returns true if (r, s) is a valid signature of hash for pub:
+with w = 1/s, u1 = e*w and u2 = r*w, the point X = u1*G + u2*Q = (e + r*d)/s * G = kG, so its
+x-coordinate mod N must be r. r and s must be in [1, N-1] and pub is checked with
+ValidatePublicKey, it comes from outside.
+*/
+func Verify(pub *PublicKey, hash []byte, r, s *big.Int) bool {
+	curve := pub.Curve
+	N := curve.N
+	if r.Sign() <= 0 || r.Cmp(N) >= 0 || s.Sign() <= 0 || s.Cmp(N) >= 0 {
+		return false
+	}
+	if err := curve.ValidatePublicKey(pub.Q); err != nil {
+		return false
+	}
+	w, err := core.NewScalar(N, s.Bytes()).Inverse()
+	if err != nil {
+		return false
+	}
+	e := hashToScalar(N, hash)
+	u1 := e.Mul(w)
+	u2 := core.NewScalar(N, r.Bytes()).Mul(w)
+	X := curve.DoubleScalarMult(u1.Bytes(), curve.G, u2.Bytes(), pub.Q)
+	if X.Infinity {
+		return false
+	}
+	v := new(big.Int).Mod(X.X, N)
+	return v.Cmp(r) == 0
+}
+
+// VerifyLowS is Verify but also rejects signatures that are not in the low-S form.
+func VerifyLowS(pub *PublicKey, hash []byte, r, s *big.Int) bool {
+	return IsLowS(pub.Curve, s) && Verify(pub, hash, r, s)
+}
+
+// ECDSAExample signs and verifies a message on P-256 and on secp256k1, which has A = 0.
+func ECDSAExample() {
+	fmt.Println("------ ECDSA ------")
+	for _, curve := range []*core.EllipticCurve{core.P256(), core.Secp256k1()} {
+		if err := ecdsaExample(curve); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+}
+
+func ecdsaExample(curve *core.EllipticCurve) error {
+	priv, err := GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte("hello, world"))
+	r, s, err := SignLowS(rand.Reader, priv, digest[:])
+	if err != nil {
+		return err
+	}
+	fmt.Println(curve.Name, "signature", r, s)
+	fmt.Println("ECDSA works?", VerifyLowS(&priv.PublicKey, digest[:], r, s))
+	return nil
+}
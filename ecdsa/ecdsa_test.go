@@ -0,0 +1,113 @@
+package ecdsa
+
+import (
+	goecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"ecc/core"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// toGo converts a P256() key to a crypto/ecdsa key.
+func toGo(priv *PrivateKey) *goecdsa.PrivateKey {
+	return &goecdsa.PrivateKey{
+		PublicKey: goecdsa.PublicKey{Curve: elliptic.P256(), X: priv.Q.X, Y: priv.Q.Y},
+		D:         priv.D.BigInt(),
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	// secp256k1 has A = 0 and the Brainpool curves a random A, crypto/ecdsa can't do either
+	curves := []*core.EllipticCurve{core.P256(), core.Secp256k1(), core.BrainpoolP256r1(), core.P521()}
+	digest := sha256.Sum256([]byte("hello, world"))
+	other := sha256.Sum256([]byte("hello, world!"))
+	for _, curve := range curves {
+		priv, err := GenerateKey(curve, rand.Reader)
+		assert.NoError(t, err)
+		r, s, err := Sign(rand.Reader, priv, digest[:])
+		assert.NoError(t, err)
+		assert.True(t, Verify(&priv.PublicKey, digest[:], r, s), curve.Name)
+		assert.False(t, Verify(&priv.PublicKey, other[:], r, s), curve.Name)
+		assert.False(t, Verify(&priv.PublicKey, digest[:], s, r), curve.Name)
+
+		wrong, err := GenerateKey(curve, rand.Reader)
+		assert.NoError(t, err)
+		assert.False(t, Verify(&wrong.PublicKey, digest[:], r, s), curve.Name)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	curve := core.P256()
+	priv, err := GenerateKey(curve, rand.Reader)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello, world"))
+	r, s, err := Sign(rand.Reader, priv, digest[:])
+	assert.NoError(t, err)
+
+	// r and s must be in [1, N-1]
+	zero := big.NewInt(0)
+	assert.False(t, Verify(&priv.PublicKey, digest[:], zero, s))
+	assert.False(t, Verify(&priv.PublicKey, digest[:], r, zero))
+	assert.False(t, Verify(&priv.PublicKey, digest[:], new(big.Int).Add(r, curve.N), s))
+	assert.False(t, Verify(&priv.PublicKey, digest[:], r, new(big.Int).Add(s, curve.N)))
+
+	// the public key must be on the curve and not the point at infinity
+	offCurve := &PublicKey{Curve: curve, Q: &core.Point{X: priv.Q.X, Y: new(big.Int).Add(priv.Q.Y, big.NewInt(1))}}
+	assert.False(t, Verify(offCurve, digest[:], r, s))
+	assert.False(t, Verify(&PublicKey{Curve: curve, Q: core.Infinity()}, digest[:], r, s))
+}
+
+// TestInterop checks that signatures on P256() verify with crypto/ecdsa and vice versa.
+func TestInterop(t *testing.T) {
+	// SHA-512 is longer than N, both sides must truncate it the same way
+	d256 := sha256.Sum256([]byte("hello, world"))
+	d512 := sha512.Sum512([]byte("hello, world"))
+	for _, digest := range [][]byte{d256[:], d512[:]} {
+		for i := 0; i < 5; i++ {
+			priv, err := GenerateKey(core.P256(), rand.Reader)
+			assert.NoError(t, err)
+			goPriv := toGo(priv)
+
+			r, s, err := Sign(rand.Reader, priv, digest)
+			assert.NoError(t, err)
+			assert.True(t, goecdsa.Verify(&goPriv.PublicKey, digest, r, s))
+
+			r, s, err = goecdsa.Sign(rand.Reader, goPriv, digest)
+			assert.NoError(t, err)
+			assert.True(t, Verify(&priv.PublicKey, digest, r, s))
+		}
+	}
+
+	// a key from crypto/ecdsa works too
+	goPriv, err := goecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	r, s, err := goecdsa.Sign(rand.Reader, goPriv, d256[:])
+	assert.NoError(t, err)
+	pub := &PublicKey{Curve: core.P256(), Q: &core.Point{X: goPriv.X, Y: goPriv.Y}}
+	assert.True(t, Verify(pub, d256[:], r, s))
+}
+
+func TestLowS(t *testing.T) {
+	curve := core.Secp256k1()
+	priv, err := GenerateKey(curve, rand.Reader)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello, world"))
+	for i := 0; i < 10; i++ {
+		r, s, err := SignLowS(rand.Reader, priv, digest[:])
+		assert.NoError(t, err)
+		assert.True(t, IsLowS(curve, s))
+		assert.True(t, VerifyLowS(&priv.PublicKey, digest[:], r, s))
+
+		// (r, N-s) is just as valid, but not low-S
+		high := new(big.Int).Sub(curve.N, s)
+		assert.False(t, IsLowS(curve, high))
+		assert.True(t, Verify(&priv.PublicKey, digest[:], r, high))
+		assert.False(t, VerifyLowS(&priv.PublicKey, digest[:], r, high))
+		assert.Equal(t, s, NormalizeS(curve, high))
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Package field implements arithmetic in a prime field F_p where p is chosen at runtime.
+
+Elements are stored as fixed arrays of 64-bit limbs in Montgomery form, so that a multiplication
+is a handful of 64x64 bit multiplications and no division, unlike big.Int Mul followed by Mod.
+This is the approach of goff (https://hackmd.io/@zkteam/goff), except that goff generates code
+for one fixed p, while here p and the number of limbs are only known at runtime.
+*/
+package field
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// MaxLimbs is the number of 64-bit limbs in an Element, enough for a 576-bit p, which covers P-521.
+const MaxLimbs = 9
+
+/*
+Element is an element of F_p in Montgomery form, i.e. x is stored as xR mod p where R = 2^(64n)
+and n is the number of limbs of p. The limbs are little-endian and the ones above n are zero.
+An Element only has a meaning together with the Field it came from. The zero value is 0.
+*/
+type Element [MaxLimbs]uint64
+
+// Field is the prime field F_p, its methods do the arithmetic on Elements.
+type Field struct {
+	p       Element  // p, not in Montgomery form
+	n       int      // number of limbs of p
+	pInv    uint64   // -p^(-1) mod 2^64
+	r2      Element  // R^2 mod p, to convert into Montgomery form
+	one     Element  // R mod p, i.e. 1 in Montgomery form
+	modulus *big.Int // p
+
+	// square roots, see initSqrt()
+	sqrtMethod sqrtMethod
+	sqrtExp    *big.Int // exponent of the first step
+	sqrtS      int      // Tonelli-Shanks: p-1 = q*2^s with q odd
+	sqrtQ      *big.Int
+	sqrtC      Element // Tonelli-Shanks: z^q for a non-residue z
+}
+
+/*
+NewField returns the field F_p. p has to be odd because Montgomery multiplication needs
+p to be invertible mod 2^64, and at most 64*MaxLimbs bits long.
+NewField doesn't check that p is prime, Inverse and Exp only make sense if it is.
+*/
+func NewField(p *big.Int) (*Field, error) {
+	if p.Sign() <= 0 || p.Bit(0) == 0 || p.Cmp(big.NewInt(1)) == 0 {
+		return nil, errors.New("field: modulus must be odd and greater than 1")
+	}
+	if p.BitLen() > 64*MaxLimbs {
+		return nil, errors.New("field: modulus is too large")
+	}
+	f := &Field{n: (p.BitLen() + 63) / 64, modulus: new(big.Int).Set(p)}
+	f.p = f.limbs(p)
+	// Newton iteration for p^(-1) mod 2^64, every step doubles the number of correct bits
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.p[0]*inv
+	}
+	f.pInv = -inv
+	r := new(big.Int).Lsh(big.NewInt(1), uint(64*f.n))
+	f.one = f.limbs(new(big.Int).Mod(r, p))
+	r.Mul(r, r)
+	f.r2 = f.limbs(r.Mod(r, p))
+	f.initSqrt()
+	return f, nil
+}
+
+// limbs returns x as little-endian limbs, x must be less than 2^(64n).
+func (f *Field) limbs(x *big.Int) Element {
+	var z Element
+	b := make([]byte, 8*f.n)
+	x.FillBytes(b)
+	for i := 0; i < f.n; i++ {
+		z[i] = binary.BigEndian.Uint64(b[8*(f.n-1-i):])
+	}
+	return z
+}
+
+// Modulus returns a copy of p.
+func (f *Field) Modulus() *big.Int {
+	return new(big.Int).Set(f.modulus)
+}
+
+// SetBigInt sets z to x mod p and returns z.
+func (f *Field) SetBigInt(z *Element, x *big.Int) *Element {
+	*z = f.limbs(new(big.Int).Mod(x, f.modulus))
+	return f.Mul(z, z, &f.r2)
+}
+
+// BigInt returns x as a big.Int in [0, p).
+func (f *Field) BigInt(x *Element) *big.Int {
+	// multiplying by 1 (not in Montgomery form) divides by R
+	var t Element
+	t[0] = 1
+	f.Mul(&t, x, &t)
+	b := make([]byte, 8*f.n)
+	for i := 0; i < f.n; i++ {
+		binary.BigEndian.PutUint64(b[8*(f.n-1-i):], t[i])
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// SetOne sets z to 1 and returns z.
+func (f *Field) SetOne(z *Element) *Element {
+	*z = f.one
+	return z
+}
+
+// IsZero returns true if x is 0.
+func (f *Field) IsZero(x *Element) bool {
+	var acc uint64
+	for i := 0; i < f.n; i++ {
+		acc |= x[i]
+	}
+	return acc == 0
+}
+
+// Equal returns true if x and y are equal.
+func (f *Field) Equal(x, y *Element) bool {
+	var acc uint64
+	for i := 0; i < f.n; i++ {
+		acc |= x[i] ^ y[i]
+	}
+	return acc == 0
+}
+
+// Add sets z = x+y mod p and returns z.
+func (f *Field) Add(z, x, y *Element) *Element {
+	var t Element
+	var carry uint64
+	for i := 0; i < f.n; i++ {
+		t[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	f.reduce(&t, carry)
+	*z = t
+	return z
+}
+
+// Sub sets z = x-y mod p and returns z.
+func (f *Field) Sub(z, x, y *Element) *Element {
+	var t Element
+	var borrow uint64
+	for i := 0; i < f.n; i++ {
+		t[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	if borrow != 0 {
+		var carry uint64
+		for i := 0; i < f.n; i++ {
+			t[i], carry = bits.Add64(t[i], f.p[i], carry)
+		}
+	}
+	*z = t
+	return z
+}
+
+// Neg sets z = -x mod p and returns z.
+func (f *Field) Neg(z, x *Element) *Element {
+	var zero Element
+	return f.Sub(z, &zero, x)
+}
+
+/*
+Mul sets z = x*y mod p and returns z, both x and y in Montgomery form, i.e. it computes
+xR*yR/R = xyR mod p. This is the CIOS (coarsely integrated operand scanning) method from
+Koç et al., "Analyzing and comparing Montgomery multiplication algorithms": for every limb of y,
+add x*y[i] to the accumulator and then add a multiple of p that clears its lowest limb, so that
+it can be shifted down by one limb. The result is less than 2p, one conditional subtraction
+brings it into [0, p).
+*/
+func (f *Field) Mul(z, x, y *Element) *Element {
+	n := f.n
+	var t [MaxLimbs + 2]uint64
+	for i := 0; i < n; i++ {
+		// t += x*y[i]
+		var c, hi, lo, carry uint64
+		for j := 0; j < n; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j], c = lo, hi
+		}
+		t[n], carry = bits.Add64(t[n], c, 0)
+		t[n+1] = carry
+
+		// t = (t + m*p) / 2^64 where m is chosen so that the lowest limb becomes 0
+		m := t[0] * f.pInv
+		hi, lo = bits.Mul64(m, f.p[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(m, f.p[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j-1], c = lo, hi
+		}
+		t[n-1], carry = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + carry
+	}
+	var r Element
+	copy(r[:n], t[:n])
+	f.reduce(&r, t[n])
+	*z = r
+	return z
+}
+
+// Square sets z = x^2 mod p and returns z.
+func (f *Field) Square(z, x *Element) *Element {
+	return f.Mul(z, x, x)
+}
+
+// Exp sets z = x^e mod p and returns z, e must not be negative.
+func (f *Field) Exp(z, x *Element, e *big.Int) *Element {
+	base := *x
+	r := f.one
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		f.Square(&r, &r)
+		if e.Bit(i) == 1 {
+			f.Mul(&r, &r, &base)
+		}
+	}
+	*z = r
+	return z
+}
+
+// Inverse sets z = 1/x mod p and returns z, using Fermat's little theorem x^(p-2) = x^(-1).
+// The inverse of 0 is 0.
+func (f *Field) Inverse(z, x *Element) *Element {
+	e := new(big.Int).Sub(f.modulus, big.NewInt(2))
+	return f.Exp(z, x, e)
+}
+
+// reduce subtracts p from the (n+1)-limb number (hi, t) if it is at least p, assuming it is less than 2p.
+func (f *Field) reduce(t *Element, hi uint64) {
+	var s Element
+	var borrow uint64
+	for i := 0; i < f.n; i++ {
+		s[i], borrow = bits.Sub64(t[i], f.p[i], borrow)
+	}
+	// t >= p iff there's no borrow out of the top limb hi
+	if hi != 0 || borrow == 0 {
+		*t = s
+	}
+}
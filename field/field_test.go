@@ -0,0 +1,173 @@
+package field
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertBig compares big.Ints by value, assert.Equal would also compare their internal representation.
+func assertBig(t *testing.T, want, got *big.Int, msgAndArgs ...interface{}) {
+	assert.Equal(t, want.String(), got.String(), msgAndArgs...)
+}
+
+// testPrimes covers 1 to 9 limbs, including primes right below a limb boundary.
+func testPrimes(t *testing.T) []*big.Int {
+	primes := []*big.Int{
+		big.NewInt(3),
+		big.NewInt(13),
+		big.NewInt(3623),
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 61), big.NewInt(1)),  // 2^61-1
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(59)), // largest 64-bit prime
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 521), big.NewInt(1)), // P-521
+	}
+	p256, _ := new(big.Int).SetString("115792089210356248762697446949407573530086143415290314195533631308867097853951", 10)
+	primes = append(primes, p256)
+	for _, size := range []int{100, 192, 224, 384, 575, 576} {
+		p, err := rand.Prime(rand.Reader, size)
+		assert.NoError(t, err)
+		primes = append(primes, p)
+	}
+	return primes
+}
+
+func TestNewField(t *testing.T) {
+	_, err := NewField(big.NewInt(16))
+	assert.Error(t, err)
+	_, err = NewField(big.NewInt(1))
+	assert.Error(t, err)
+	_, err = NewField(big.NewInt(-7))
+	assert.Error(t, err)
+	_, err = NewField(new(big.Int).Lsh(big.NewInt(1), 64*MaxLimbs+1))
+	assert.Error(t, err)
+	f, err := NewField(big.NewInt(13))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(13), f.Modulus())
+}
+
+// TestFieldDifferential checks every operation against big.Int on random inputs.
+func TestFieldDifferential(t *testing.T) {
+	for _, p := range testPrimes(t) {
+		f, err := NewField(p)
+		assert.NoError(t, err)
+		for i := 0; i < 50; i++ {
+			a, _ := rand.Int(rand.Reader, p)
+			b, _ := rand.Int(rand.Reader, p)
+			if i == 0 {
+				a.Sub(p, big.NewInt(1)) // p-1, the largest element
+			}
+			var x, y, z Element
+			f.SetBigInt(&x, a)
+			f.SetBigInt(&y, b)
+			assertBig(t, a, f.BigInt(&x))
+
+			want := new(big.Int)
+			assertBig(t, want.Mod(want.Add(a, b), p), f.BigInt(f.Add(&z, &x, &y)), "add mod %v", p)
+			assertBig(t, want.Mod(want.Sub(a, b), p), f.BigInt(f.Sub(&z, &x, &y)), "sub mod %v", p)
+			assertBig(t, want.Mod(want.Neg(a), p), f.BigInt(f.Neg(&z, &x)), "neg mod %v", p)
+			assertBig(t, want.Mod(want.Mul(a, b), p), f.BigInt(f.Mul(&z, &x, &y)), "mul mod %v", p)
+			assertBig(t, want.Mod(want.Mul(a, a), p), f.BigInt(f.Square(&z, &x)), "square mod %v", p)
+			e, _ := rand.Int(rand.Reader, p)
+			assertBig(t, want.Exp(a, e, p), f.BigInt(f.Exp(&z, &x, e)), "exp mod %v", p)
+			if a.Sign() != 0 {
+				assertBig(t, want.ModInverse(a, p), f.BigInt(f.Inverse(&z, &x)), "inverse mod %v", p)
+			}
+			assert.Equal(t, a.Cmp(b) == 0, f.Equal(&x, &y))
+		}
+	}
+}
+
+func TestFieldEdgeCases(t *testing.T) {
+	p := big.NewInt(3623)
+	f, _ := NewField(p)
+	var x, y, z Element
+	// SetBigInt reduces negative and large values
+	f.SetBigInt(&x, big.NewInt(-1))
+	assertBig(t, big.NewInt(3622), f.BigInt(&x))
+	f.SetBigInt(&x, big.NewInt(3623*5+7))
+	assertBig(t, big.NewInt(7), f.BigInt(&x))
+
+	// zero and one
+	assert.True(t, f.IsZero(&z))
+	assertBig(t, big.NewInt(0), f.BigInt(&z))
+	f.SetOne(&y)
+	assertBig(t, big.NewInt(1), f.BigInt(&y))
+	assert.False(t, f.IsZero(&y))
+	assert.True(t, f.IsZero(f.Inverse(&x, &z)))
+	f.SetBigInt(&x, big.NewInt(7))
+	assert.True(t, f.Equal(f.Exp(&z, &x, big.NewInt(0)), &y))
+
+	// aliasing the output with the inputs
+	f.SetBigInt(&x, big.NewInt(100))
+	f.Mul(&x, &x, &x)
+	assertBig(t, big.NewInt(10000%3623), f.BigInt(&x))
+	f.Add(&x, &x, &x)
+	assertBig(t, big.NewInt(2*10000%3623), f.BigInt(&x))
+	f.Sub(&x, &x, &x)
+	assert.True(t, f.IsZero(&x))
+}
+
+func BenchmarkMulP256(b *testing.B) {
+	p, _ := new(big.Int).SetString("115792089210356248762697446949407573530086143415290314195533631308867097853951", 10)
+	f, _ := NewField(p)
+	a, _ := rand.Int(rand.Reader, p)
+	var x Element
+	f.SetBigInt(&x, a)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Mul(&x, &x, &x)
+	}
+}
+
+func BenchmarkBigIntMulP256(b *testing.B) {
+	p, _ := new(big.Int).SetString("115792089210356248762697446949407573530086143415290314195533631308867097853951", 10)
+	x, _ := rand.Int(rand.Reader, p)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(x, x)
+		x.Mod(x, p)
+	}
+}
+
+// TestSqrt covers all three methods: p = 3 mod 4, p = 5 mod 8 and p = 1 mod 8 (Tonelli-Shanks).
+func TestSqrt(t *testing.T) {
+	p224, _ := new(big.Int).SetString("26959946667150639794667015087019630673557916260026308143510066298881", 10) // 2^224-2^96+1, s = 96
+	primes := append(testPrimes(t), big.NewInt(5), big.NewInt(17), big.NewInt(29), big.NewInt(41), big.NewInt(97), big.NewInt(257), p224)
+	for _, p := range primes {
+		f, err := NewField(p)
+		assert.NoError(t, err)
+		for i := 0; i < 30; i++ {
+			a, _ := rand.Int(rand.Reader, p)
+			if i == 0 {
+				a.SetInt64(0)
+			}
+			var x, z Element
+			f.SetBigInt(&x, a)
+			want := new(big.Int).ModSqrt(a, p)
+			got := f.Sqrt(&z, &x)
+			if want == nil {
+				assert.Nil(t, got, "p=%v a=%v", p, a)
+				continue
+			}
+			if assert.NotNil(t, got, "p=%v a=%v", p, a) {
+				y := f.BigInt(got)
+				// either root is fine
+				if y.Cmp(want) != 0 {
+					want.Sub(p, want)
+				}
+				assertBig(t, want, y, "p=%v a=%v", p, a)
+			}
+		}
+	}
+}
+
+func TestSqrtNonSquareLeavesZ(t *testing.T) {
+	f, _ := NewField(big.NewInt(17))
+	var x, z Element
+	f.SetBigInt(&x, big.NewInt(3)) // 3 is not a square mod 17
+	f.SetOne(&z)
+	assert.Nil(t, f.Sqrt(&z, &x))
+	assertBig(t, big.NewInt(1), f.BigInt(&z))
+}
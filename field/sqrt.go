@@ -0,0 +1,127 @@
+package field
+
+import "math/big"
+
+// sqrtMethod is the square root algorithm for a given p, see initSqrt().
+type sqrtMethod int
+
+const (
+	sqrt3Mod4         sqrtMethod = iota // p = 3 mod 4, x^((p+1)/4)
+	sqrt5Mod8                           // p = 5 mod 8, Atkin's method
+	sqrtTonelliShanks                   // p = 1 mod 8
+)
+
+/*
+initSqrt precomputes what Sqrt needs for this p, so that Sqrt is just exponentiations.
+For Tonelli-Shanks it writes p-1 = q*2^s with q odd and looks for a non-residue z by trying
+2, 3, 4, ... For a prime p about half of the elements are non-residues, so this ends quickly.
+If p isn't prime there might be none, the search gives up and Sqrt will fail its final check.
+*/
+func (f *Field) initSqrt() {
+	p := f.modulus
+	switch {
+	case p.Bit(1) == 1: // p = 3 mod 4
+		f.sqrtMethod = sqrt3Mod4
+		f.sqrtExp = new(big.Int).Add(p, big.NewInt(1))
+		f.sqrtExp.Rsh(f.sqrtExp, 2)
+	case p.Bit(2) == 1: // p = 5 mod 8
+		f.sqrtMethod = sqrt5Mod8
+		f.sqrtExp = new(big.Int).Sub(p, big.NewInt(5))
+		f.sqrtExp.Rsh(f.sqrtExp, 3)
+	default: // p = 1 mod 8
+		f.sqrtMethod = sqrtTonelliShanks
+		q := new(big.Int).Sub(p, big.NewInt(1))
+		for q.Bit(0) == 0 {
+			q.Rsh(q, 1)
+			f.sqrtS++
+		}
+		f.sqrtExp = new(big.Int).Add(q, big.NewInt(1))
+		f.sqrtExp.Rsh(f.sqrtExp, 1)
+		f.sqrtQ = q
+		euler := new(big.Int).Rsh(p, 1) // (p-1)/2
+		var minusOne, z, l Element
+		f.Neg(&minusOne, &f.one)
+		for i := int64(2); i < 1<<16 && big.NewInt(i).Cmp(p) < 0; i++ {
+			f.SetBigInt(&z, big.NewInt(i))
+			if f.Equal(f.Exp(&l, &z, euler), &minusOne) {
+				f.Exp(&f.sqrtC, &z, q)
+				break
+			}
+		}
+	}
+}
+
+/*
+Sqrt sets z to a square root of x and returns z. If x is not a square, Sqrt returns nil and
+leaves z unchanged, like big.Int's ModSqrt. The other root is -z.
+
+- p = 3 mod 4: y = x^((p+1)/4), since y^2 = x^((p+1)/2) = x * x^((p-1)/2) = x by Euler's criterion.
+- p = 5 mod 8: Atkin's method, t = (2x)^((p-5)/8), i = 2x*t^2, y = x*t*(i-1). Here i is a square
+root of -1, which is what the 3 mod 4 trick is missing.
+- p = 1 mod 8: Tonelli-Shanks, see tonelliShanks().
+
+All three compute a candidate and check that it squares to x, that's how non-squares are caught.
+*/
+func (f *Field) Sqrt(z, x *Element) *Element {
+	var y, t Element
+	switch f.sqrtMethod {
+	case sqrt3Mod4:
+		f.Exp(&y, x, f.sqrtExp)
+	case sqrt5Mod8:
+		var x2, i Element
+		f.Add(&x2, x, x)
+		f.Exp(&t, &x2, f.sqrtExp)
+		f.Square(&i, &t)
+		f.Mul(&i, &i, &x2)
+		f.Sub(&i, &i, &f.one)
+		f.Mul(&y, x, &t)
+		f.Mul(&y, &y, &i)
+	default:
+		f.tonelliShanks(&y, x)
+	}
+	if !f.Equal(f.Square(&t, &y), x) {
+		return nil
+	}
+	*z = y
+	return z
+}
+
+/*
+tonelliShanks sets y to the square root of x if there is one, with p-1 = q*2^s.
+Start with y = x^((q+1)/2) and t = x^q, so that y^2 = x*t. t lives in the subgroup of order 2^s,
+and every step multiplies y by a power b of c = z^q (z a non-residue, so c generates that subgroup)
+so that t = t*b^2 has a smaller order, until t = 1 and y^2 = x.
+If x isn't a square, at some point t has the full order 2^m and the loop stops.
+*/
+func (f *Field) tonelliShanks(y, x *Element) {
+	if f.IsZero(x) {
+		*y = Element{}
+		return
+	}
+	var t, c, b, t2 Element
+	f.Exp(y, x, f.sqrtExp)
+	f.Exp(&t, x, f.sqrtQ)
+	c = f.sqrtC
+	m := f.sqrtS
+	for !f.Equal(&t, &f.one) {
+		// find the least i with t^(2^i) = 1
+		i := 0
+		t2 = t
+		for !f.Equal(&t2, &f.one) {
+			f.Square(&t2, &t2)
+			i++
+			if i == m {
+				return // x is not a square
+			}
+		}
+		// b = c^(2^(m-i-1))
+		b = c
+		for j := 0; j < m-i-1; j++ {
+			f.Square(&b, &b)
+		}
+		f.Mul(y, y, &b)
+		f.Square(&c, &b)
+		f.Mul(&t, &t, &c)
+		m = i
+	}
+}
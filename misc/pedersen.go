@@ -1,27 +1,31 @@
 package misc
 
 import (
-	"crypto/elliptic"
 	"crypto/rand"
+	"ecc/core"
 	"fmt"
-	"math/big"
 )
 
+// PedersenCommitmentExample runs the commitment on P-256 and Ed25519, any core.Group works.
 func PedersenCommitmentExample() {
-	curve := elliptic.P256()
+	for _, group := range []core.Group{core.P256(), core.Ed25519()} {
+		pedersenExample(group)
+	}
+}
+
+func pedersenExample(group core.Group) {
 	// set up G= b1*P and H=b2*P where P is base point
-	b1 := make([]byte, 32)
-	_, err := rand.Read(b1)
+	b1, err := rand.Int(rand.Reader, group.Order())
 	if err != nil {
 		fmt.Println(err)
 	}
-	Gx, Gy := curve.ScalarBaseMult(b1)
-	b2 := make([]byte, 32)
-	_, err = rand.Read(b2)
+	// b1 and b2 must stay secret, whoever knows log_G(H) can open C to any other message
+	G := group.ScalarMultSecret(b1.Bytes(), group.Generator())
+	b2, err := rand.Int(rand.Reader, group.Order())
 	if err != nil {
 		fmt.Println(err)
 	}
-	Hx, Hy := curve.ScalarBaseMult(b2)
+	H := group.ScalarMultSecret(b2.Bytes(), group.Generator())
 
 	// message
 	m := make([]byte, 32)
@@ -37,23 +41,26 @@ func PedersenCommitmentExample() {
 	}
 
 	// commit
-	Cx, Cy, _ := commit(curve, m, r, Gx, Gy, Hx, Hy)
-	fmt.Println(Cx, Cy)
+	C, _ := commit(group, m, r, G, H)
+	fmt.Println(C.X, C.Y)
 	// open
-	fmt.Println(open(curve, m, r, Gx, Gy, Hx, Hy, Cx, Cy))
+	fmt.Println(open(group, m, r, G, H, C))
 }
 
-func commit(curve elliptic.Curve, m, r []byte, Gx, Gy, Hx, Hy *big.Int) (Cx, Cy *big.Int, err error) {
-	tmp1x, tmp1y := curve.ScalarMult(Gx, Gy, m)
-	tmp2x, tmp2y := curve.ScalarMult(Hx, Hy, r)
-	Cx, Cy = curve.Add(tmp1x, tmp1y, tmp2x, tmp2y)
-	return Cx, Cy, nil
+/*
+commit returns the commitment C = mG + rH. This used to be one DoubleScalarMult, which is faster,
+but Shamir's trick looks up the table by the bits of m and r, i.e. its timing depends on them, and
+m and r are secret until the commitment is opened, r even forever if it's reused. So commit does
+two ScalarMultSecret instead, don't change it back. open can use DoubleScalarMult, by then m and r
+are public.
+*/
+func commit(group core.Group, m, r []byte, G, H *core.Point) (C *core.Point, err error) {
+	C = group.Add(group.ScalarMultSecret(m, G), group.ScalarMultSecret(r, H))
+	return C, nil
 }
 
-func open(curve elliptic.Curve, m, r []byte, Gx, Gy, Hx, Hy, Cx, Cy *big.Int) bool {
-	tmp1x, tmp1y := curve.ScalarMult(Gx, Gy, m)
-	tmp2x, tmp2y := curve.ScalarMult(Hx, Hy, r)
-	Dx, Dy := curve.Add(tmp1x, tmp1y, tmp2x, tmp2y)
-
-	return Dx.Cmp(Cx) == 0 && Dy.Cmp(Cy) == 0
+// open recomputes mG + rH and checks that it matches the commitment C.
+func open(group core.Group, m, r []byte, G, H, C *core.Point) bool {
+	D := group.DoubleScalarMult(m, G, r, H)
+	return group.Equal(D, C)
 }
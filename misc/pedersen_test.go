@@ -0,0 +1,26 @@
+package misc
+
+import (
+	"crypto/rand"
+	"ecc/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPedersen(t *testing.T) {
+	for _, group := range []core.Group{core.P256(), core.Ed25519()} {
+		G := group.Generator()
+		b, _ := rand.Int(rand.Reader, group.Order())
+		H := group.ScalarBaseMult(b.Bytes())
+		m := make([]byte, 32)
+		r := make([]byte, 32)
+		_, _ = rand.Read(m)
+		_, _ = rand.Read(r)
+		C, err := commit(group, m, r, G, H)
+		assert.NoError(t, err)
+		assert.True(t, open(group, m, r, G, H, C))
+		m[0] ^= 1
+		assert.False(t, open(group, m, r, G, H, C))
+	}
+}